@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"sunny_land/src/engine/levelcheck"
+	"sunny_land/src/engine/vfs"
+	"sunny_land/src/game/component/ai/behavior"
+)
+
+// 关卡校验命令，不创建窗口，在项目根目录下运行：go run ./cmd/levelcheck
+// 存在错误时退出码为1，可用于合并前的检查
+func main() {
+	mapDir := flag.String("dir", "assets/maps", "directory containing .tmj maps")
+	flag.Parse()
+
+	handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo})
+	slog.SetDefault(slog.New(handler))
+
+	// 与游戏相同的挂载，资源包与模组中的文件同样参与校验
+	vfs.GetVFS().MountAssets(vfs.AssetsArchive, vfs.ModsDir)
+
+	validator := levelcheck.NewLevelValidator()
+	// 游戏内置的AI行为，ai.RegisterBehaviors依赖SDL，这里使用不依赖SDL的名称列表
+	validator.SetAIBehaviors(behavior.Names())
+	var mapCount int
+	if flag.NArg() > 0 {
+		// 指定了地图文件时只校验这些地图
+		for _, mapPath := range flag.Args() {
+			validator.ValidateMap(mapPath)
+		}
+		mapCount = flag.NArg()
+	} else {
+		mapCount = validator.ValidateDir(*mapDir)
+	}
+
+	for _, issue := range validator.GetIssues() {
+		fmt.Println(issue)
+	}
+
+	errorCount := validator.ErrorCount()
+	slog.Info("level check finished",
+		slog.Int("maps", mapCount),
+		slog.Int("errors", errorCount),
+		slog.Int("warnings", len(validator.GetIssues())-errorCount))
+	if errorCount > 0 {
+		os.Exit(1)
+	}
+}
@@ -2,6 +2,7 @@ package audio
 
 import (
 	"log/slog"
+	"path/filepath"
 	"sunny_land/src/engine/resource"
 )
 
@@ -18,6 +19,8 @@ type AudioPlayer struct {
 	currentMusicPath string
 	// 当前正在播放音乐对象
 	currentMusicAudio resource.IAudio
+	// 当前音乐是否循环播放
+	currentMusicLoop bool
 }
 
 // 创建音乐播放器
@@ -60,10 +63,13 @@ func (a *AudioPlayer) PlaySound(soundPath string) bool {
 func (a *AudioPlayer) PlayMusic(musicPath string, loop bool) bool {
 	// 如果当前音乐已经在播放，则不重复播放
 	if a.currentMusicPath == musicPath && a.currentMusicAudio != nil && a.currentMusicAudio.IsPlaying() {
+		// 记录当前场景也在使用该音乐，避免上一个场景释放时被卸载
+		a.resourceManager.RetainMusic(musicPath)
 		return true
 	}
 
 	a.currentMusicPath = musicPath
+	a.currentMusicLoop = loop
 	if a.currentMusicAudio != nil {
 		a.currentMusicAudio.Close()
 		a.currentMusicAudio = nil
@@ -154,3 +160,15 @@ func (a *AudioPlayer) SetSoundVolume(volume float32) {
 func (a *AudioPlayer) GetSoundVolume() float32 {
 	return a.resourceManager.GetSoundVolume()
 }
+
+/**
+ * @brief 音频文件被重新加载后调用，如果是当前音乐则重新播放(旧音乐对象已被关闭)
+ * @param path 被重新加载的文件路径
+ */
+func (a *AudioPlayer) OnAudioReloaded(path string) {
+	if a.currentMusicPath == "" || filepath.Clean(a.currentMusicPath) != filepath.Clean(path) {
+		return
+	}
+	a.currentMusicAudio = nil
+	a.PlayMusic(a.currentMusicPath, a.currentMusicLoop)
+}
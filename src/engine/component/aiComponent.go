@@ -101,8 +101,8 @@ func (ac *AIComponent) SetBehavior(behavior IAIBehavior) {
 // 处理伤害逻辑，返回是否造成伤害
 func (ac *AIComponent) TakeDamage(damage int) bool {
 	success := false
-	healthComponent := ac.GetOwner().GetComponent(def.ComponentTypeHealth).(*HealthComponent)
-	if healthComponent != nil {
+	healthComponent, ok := ac.GetOwner().GetComponent(def.ComponentTypeHealth).(*HealthComponent)
+	if ok {
 		success = healthComponent.TakeDamage(damage)
 		// TODO: 处理伤害/死亡后的行为
 	}
@@ -111,8 +111,8 @@ func (ac *AIComponent) TakeDamage(damage int) bool {
 
 // 是否活着
 func (ac *AIComponent) IsAlive() bool {
-	healthComponent := ac.GetOwner().GetComponent(def.ComponentTypeHealth).(*HealthComponent)
-	if healthComponent != nil {
+	healthComponent, ok := ac.GetOwner().GetComponent(def.ComponentTypeHealth).(*HealthComponent)
+	if ok {
 		return healthComponent.IsAlive()
 	}
 	// 如果没有生命组件，默认返回存活状态
@@ -0,0 +1,64 @@
+package component
+
+import (
+	"log/slog"
+	"sync"
+
+	"github.com/bitly/go-simplejson"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+/**
+ * @brief AI行为工厂函数
+ * @param params 行为参数json数据(例如：{"behavior":"patrol","range":200,"speed":50})
+ * @param position 游戏对象的初始位置，行为范围以此为基准计算
+ * @return IAIBehavior 创建的行为，参数无效时返回nil
+ */
+type AIBehaviorFactory func(params *simplejson.Json, position mgl32.Vec2) IAIBehavior
+
+// AI行为注册表，行为名称 -> 工厂函数
+var aiBehaviorRegistry = struct {
+	sync.RWMutex
+	factories map[string]AIBehaviorFactory
+}{
+	factories: make(map[string]AIBehaviorFactory),
+}
+
+// 注册AI行为，重复注册会覆盖之前的工厂函数
+func RegisterAIBehavior(name string, factory AIBehaviorFactory) {
+	if name == "" || factory == nil {
+		slog.Error("register ai behavior with empty name or nil factory", slog.String("name", name))
+		return
+	}
+
+	aiBehaviorRegistry.Lock()
+	defer aiBehaviorRegistry.Unlock()
+
+	if _, ok := aiBehaviorRegistry.factories[name]; ok {
+		slog.Warn("ai behavior already registered, override", slog.String("name", name))
+	}
+	aiBehaviorRegistry.factories[name] = factory
+	slog.Debug("register ai behavior", slog.String("name", name))
+}
+
+// 检查AI行为是否已注册
+func HasAIBehavior(name string) bool {
+	aiBehaviorRegistry.RLock()
+	defer aiBehaviorRegistry.RUnlock()
+
+	_, ok := aiBehaviorRegistry.factories[name]
+	return ok
+}
+
+// 根据名称创建AI行为，未注册时返回nil
+func CreateAIBehavior(name string, params *simplejson.Json, position mgl32.Vec2) IAIBehavior {
+	aiBehaviorRegistry.RLock()
+	factory, ok := aiBehaviorRegistry.factories[name]
+	aiBehaviorRegistry.RUnlock()
+
+	if !ok {
+		slog.Error("ai behavior not registered", slog.String("name", name))
+		return nil
+	}
+	return factory(params, position)
+}
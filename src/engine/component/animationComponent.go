@@ -4,6 +4,7 @@ import (
 	"log/slog"
 
 	"sunny_land/src/engine/physics"
+	"sunny_land/src/engine/render"
 	"sunny_land/src/engine/utils/def"
 )
 
@@ -68,8 +69,8 @@ func (a *AnimationComponent) Update(dt float64, ctx physics.IContext) {
 	// 根据时间获取当前帧
 	currentFrame := a.currentAnimation.GetFrameAtTime(a.animationTimer)
 
-	// 更新精灵组件的源矩形(使用 SpriteComponent 的新方法)
-	a.spriteComponent.SetSourceRect(currentFrame.SourceRect)
+	// 更新精灵组件的源矩形及裁剪偏移
+	a.spriteComponent.SetFrame(currentFrame)
 
 	// 检查非循环动画是否已结束
 	if !a.currentAnimation.IsLooping() && a.animationTimer >= a.currentAnimation.GetTotalDuration() {
@@ -89,6 +90,27 @@ func (a *AnimationComponent) AddAnimation(animation physics.IAnimation) {
 	slog.Debug("add animation to gameObject", slog.String("animation.name", name), slog.String("gameOject.name", a.Owner.GetName()))
 }
 
+/**
+ * @brief 从Aseprite导出的json精灵表添加动画，每个帧标签对应一个同名动画，
+ * 精灵图切换为精灵表的图片
+ * @param jsonPath json文件路径
+ * @return bool 是否成功
+ */
+func (a *AnimationComponent) LoadAseprite(jsonPath string) bool {
+	sheet := render.LoadAsepriteSheet(jsonPath)
+	if sheet == nil {
+		return false
+	}
+	for _, animation := range sheet.Animations {
+		a.AddAnimation(animation)
+	}
+	if a.spriteComponent != nil && sheet.Image != "" {
+		a.spriteComponent.SetSpriteById(sheet.Image, nil)
+		a.spriteComponent.SetFrame(sheet.Frames[0])
+	}
+	return true
+}
+
 // 播放指定名称的动画
 func (a *AnimationComponent) PlayAnimation(name string) {
 	animation, ok := a.animations[name]
@@ -109,7 +131,7 @@ func (a *AnimationComponent) PlayAnimation(name string) {
 	// 立即将精灵更新到第一帧
 	if a.spriteComponent != nil && !a.currentAnimation.IsEmpty() {
 		currentFrame := a.currentAnimation.GetFrameAtTime(0.0)
-		a.spriteComponent.SetSourceRect(currentFrame.SourceRect)
+		a.spriteComponent.SetFrame(currentFrame)
 	}
 
 	slog.Debug("play animation", slog.String("animation.name", name), slog.String("gameOject.name", a.Owner.GetName()))
@@ -69,7 +69,7 @@ func (a *AudioComponent) PlaySound(soundId string, useSpatial bool) {
 	if useSpatial && a.transformComponent != nil {
 		// 这里给一个简单的功能：150像素范围内播放，否则不播放
 		// 相机中心
-		cameraCenter := a.camera.GetPosition().Add(a.camera.GetViewportSize().Mul(0.5))
+		cameraCenter := a.camera.GetPosition().Add(a.camera.GetViewSize().Mul(0.5))
 		objPos := a.transformComponent.GetPosition()
 		// 距离
 		dist := cameraCenter.Sub(objPos).Len()
@@ -0,0 +1,159 @@
+package component
+
+import (
+	"log/slog"
+	"math"
+
+	"sunny_land/src/engine/physics"
+	"sunny_land/src/engine/render"
+	"sunny_land/src/engine/utils/def"
+	emath "sunny_land/src/engine/utils/math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// 默认的光源纹理，白色径向渐变
+const DEFAULT_LIGHT_TEXTURE = "assets/textures/FX/light-radial.png"
+
+/**
+ * @brief 光源组件，在所属游戏对象的位置绘制光源精灵图照亮暗度层。
+ *
+ * 只在场景设置了环境光时生效。光源纹理可以是径向渐变，也可以是任意形状的cookie纹理(例如手电筒的锥形光)，
+ * 纹理的亮度决定光照的形状，颜色的A为强度。
+ */
+type LightComponent struct {
+	// 继承基础组件
+	Component
+	// 缓存变换组件
+	transformComponent *TransformComponent
+	// 光源精灵图
+	sprite *render.Sprite
+	// 光源在世界中的尺寸
+	size mgl32.Vec2
+	// 光源颜色，A为强度
+	color emath.FColor
+	// 光源中心相对于变换组件位置的偏移
+	offset mgl32.Vec2
+	// 闪烁幅度(强度的比例，0表示不闪烁)与频率(次/秒)
+	flickerAmount, flickerSpeed float32
+	// 闪烁计时
+	elapsed float64
+	// 是否启用
+	isEnabled bool
+}
+
+// 确保LightComponent实现了IComponent接口
+var _ physics.IComponent = (*LightComponent)(nil)
+
+/**
+ * @brief 创建光源组件
+ * @param textureId 光源纹理，空表示使用默认的径向渐变
+ * @param size 光源在世界中的尺寸
+ * @param color 光源颜色，A为强度
+ * @return *LightComponent 组件
+ */
+func NewLightComponent(textureId string, size mgl32.Vec2, color emath.FColor) *LightComponent {
+	if textureId == "" {
+		textureId = DEFAULT_LIGHT_TEXTURE
+	}
+	slog.Debug("create light component", slog.String("textureId", textureId), slog.Any("size", size), slog.Any("color", color))
+	return &LightComponent{
+		Component: Component{
+			ComponentType: def.ComponentTypeLight,
+		},
+		sprite:    render.NewSprite(textureId, nil, false),
+		size:      size,
+		color:     color,
+		isEnabled: true,
+	}
+}
+
+// 初始化
+func (lc *LightComponent) Init() {
+	if lc.Owner == nil {
+		slog.Error("light component owner is nil")
+		return
+	}
+	lc.transformComponent, _ = lc.Owner.GetComponent(def.ComponentTypeTransform).(*TransformComponent)
+	if lc.transformComponent == nil {
+		slog.Error("light component transform component is nil", slog.String("owner", lc.Owner.GetName()))
+		return
+	}
+}
+
+// 更新闪烁
+func (lc *LightComponent) Update(deltaTime float64, _ physics.IContext) {
+	lc.elapsed += deltaTime
+}
+
+// 渲染光源
+func (lc *LightComponent) Render(context physics.IContext) {
+	if !lc.isEnabled || lc.transformComponent == nil {
+		return
+	}
+	color := lc.color
+	if lc.flickerAmount > 0.0 {
+		// 两个不同频率的正弦叠加，看起来不那么规律
+		t := lc.elapsed * float64(lc.flickerSpeed) * 2.0 * math.Pi
+		wave := float32(math.Sin(t)*0.6+math.Sin(t*2.7+1.3)*0.4)*0.5 + 0.5
+		color.A *= 1.0 - lc.flickerAmount*wave
+	}
+	scale := lc.transformComponent.GetScale()
+	size := mgl32.Vec2{lc.size.X() * scale.X(), lc.size.Y() * scale.Y()}
+	context.GetRenderer().DrawLight(context.GetCamera(), lc.sprite, lc.GetCenter(), size, color)
+}
+
+// 获取光源中心的世界坐标
+func (lc *LightComponent) GetCenter() mgl32.Vec2 {
+	return lc.transformComponent.GetPosition().Add(lc.offset)
+}
+
+// 设置光源中心相对于变换组件位置的偏移
+func (lc *LightComponent) SetOffset(offset mgl32.Vec2) {
+	lc.offset = offset
+}
+
+// 获取光源在世界中的尺寸
+func (lc *LightComponent) GetSize() mgl32.Vec2 {
+	return lc.size
+}
+
+// 设置光源在世界中的尺寸
+func (lc *LightComponent) SetSize(size mgl32.Vec2) {
+	lc.size = size
+}
+
+// 获取光源颜色，A为强度
+func (lc *LightComponent) GetColor() emath.FColor {
+	return lc.color
+}
+
+// 设置光源颜色，A为强度
+func (lc *LightComponent) SetColor(color emath.FColor) {
+	lc.color = color
+}
+
+/**
+ * @brief 设置闪烁，例如火把
+ * @param amount 闪烁幅度，强度在(1-amount)~1倍之间变化，0表示不闪烁
+ * @param speed 闪烁频率(次/秒)
+ */
+func (lc *LightComponent) SetFlicker(amount, speed float32) {
+	lc.flickerAmount = emath.Clamp(amount, 0.0, 1.0)
+	lc.flickerSpeed = speed
+}
+
+// 获取光源精灵图，例如用于旋转cookie纹理的朝向
+func (lc *LightComponent) GetSprite() *render.Sprite {
+	return lc.sprite
+}
+
+// 是否启用
+func (lc *LightComponent) IsEnabled() bool {
+	return lc.isEnabled
+}
+
+// 设置是否启用
+func (lc *LightComponent) SetEnabled(enabled bool) {
+	lc.isEnabled = enabled
+}
@@ -27,6 +27,10 @@ type ParallaxComponent struct {
 	repeat emath.Vec2B
 	// 是否隐藏
 	isHidden bool
+	// 颜色调制，RGB为色调，A为不透明度
+	color emath.FColor
+	// 绘制图层，越大越靠前
+	drawLayer int
 }
 
 // 确保ParallaxComponent实现了IComponent接口
@@ -43,6 +47,7 @@ func NewParallaxComponent(textureId string, scrollFactor mgl32.Vec2, repeat math
 		sprite:       render.NewSprite(textureId, nil, false),
 		scrollFactor: scrollFactor,
 		repeat:       repeat,
+		color:        emath.FColor{R: 1.0, G: 1.0, B: 1.0, A: 1.0},
 	}
 }
 
@@ -65,10 +70,16 @@ func (pc *ParallaxComponent) Update(float64, physics.IContext) {
 
 // 渲染视差组件
 func (pc *ParallaxComponent) Render(context physics.IContext) {
-	if pc.isHidden || pc.transformComponent == nil {
+	if pc.isHidden || pc.transformComponent == nil || pc.color.A <= 0.0 {
 		return
 	}
 
+	// 设置颜色调制及绘制图层，绘制完毕后重置
+	context.GetRenderer().SetColorMod(pc.color)
+	defer context.GetRenderer().ResetColorMod()
+	context.GetRenderer().SetDrawOrder(pc.drawLayer, 0.0)
+	defer context.GetRenderer().ResetDrawOrder()
+
 	// 直接调用视差滚动绘制函数
 	context.GetRenderer().DrawSpriteWithParallax(
 		context.GetCamera(),
@@ -100,6 +111,16 @@ func (pc *ParallaxComponent) SetHidden(isHidden bool) {
 	pc.isHidden = isHidden
 }
 
+// 设置绘制图层，越大越靠前
+func (pc *ParallaxComponent) SetDrawLayer(layer int) {
+	pc.drawLayer = layer
+}
+
+// 设置颜色调制，RGB为色调，A为不透明度
+func (pc *ParallaxComponent) SetColor(color emath.FColor) {
+	pc.color = color
+}
+
 // 获取精灵图对象
 func (pc *ParallaxComponent) GetSprite() *render.Sprite {
 	return pc.sprite
@@ -119,3 +140,8 @@ func (pc *ParallaxComponent) GetRepeat() math.Vec2B {
 func (pc *ParallaxComponent) GetHidden() bool {
 	return pc.isHidden
 }
+
+// 获取颜色调制
+func (pc *ParallaxComponent) GetColor() emath.FColor {
+	return pc.color
+}
@@ -0,0 +1,276 @@
+package component
+
+import (
+	"log/slog"
+	"math/rand/v2"
+
+	"sunny_land/src/engine/physics"
+	"sunny_land/src/engine/render"
+	"sunny_land/src/engine/resource"
+	"sunny_land/src/engine/utils/def"
+	emath "sunny_land/src/engine/utils/math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// 单个粒子
+type particle struct {
+	// 中心点的世界坐标
+	position mgl32.Vec2
+	// 速度
+	velocity mgl32.Vec2
+	// 加速度
+	gravity mgl32.Vec2
+	// 旋转角度与角速度(度)
+	angle, angularVelocity float32
+	// 已存在时间与寿命(秒)
+	age, lifetime float32
+	// 使用的帧序号
+	frame int
+}
+
+/**
+ * @brief 粒子发射器组件，在所属游戏对象的位置发射粒子。
+ *
+ * 粒子在世界坐标中运动，发射器移动不会带动已发射的粒子。
+ * 每个粒子的颜色与缩放随寿命在起止值之间线性变化，绘制时同一纹理的粒子合并为一次绘制调用。
+ */
+type ParticleEmitterComponent struct {
+	// 继承基础组件
+	Component
+	// 缓存变换组件
+	transformComponent *TransformComponent
+	// 发射器配置
+	config *ParticleEmitterConfig
+	// 每一帧对应的精灵图
+	sprites []*render.Sprite
+	// 每一帧的尺寸
+	frameSizes []mgl32.Vec2
+	// 存活的粒子
+	particles []particle
+	// 发射器相对于变换组件位置的偏移
+	offset mgl32.Vec2
+	// 发射区域大小，以发射器位置为中心
+	spawnArea mgl32.Vec2
+	// 是否已经开始发射，第一次更新时开始，以便添加组件后还能设置偏移与发射区域
+	started bool
+	// 是否正在持续发射
+	isEmitting bool
+	// 已持续发射的时间
+	elapsed float64
+	// 不足一个粒子的发射量，累积到下一帧
+	emitAccumulator float64
+}
+
+// 确保ParticleEmitterComponent实现了IComponent接口
+var _ physics.IComponent = (*ParticleEmitterComponent)(nil)
+
+/**
+ * @brief 创建粒子发射器组件
+ * @param config 发射器配置
+ * @param resourceManager 资源管理器，用于获取整张纹理作为帧时的尺寸
+ * @return *ParticleEmitterComponent 组件，配置为nil时返回nil
+ */
+func NewParticleEmitterComponent(config *ParticleEmitterConfig, resourceManager *resource.ResourceManager) *ParticleEmitterComponent {
+	if config == nil {
+		slog.Error("particle emitter config is nil")
+		return nil
+	}
+	pec := &ParticleEmitterComponent{
+		Component: Component{
+			ComponentType: def.ComponentTypeParticleEmitter,
+		},
+		config:    config,
+		particles: make([]particle, 0, config.MaxParticles),
+		spawnArea: config.SpawnArea,
+	}
+	if len(config.Frames) == 0 {
+		pec.sprites = append(pec.sprites, render.NewSprite(config.Texture, nil, false))
+		pec.frameSizes = append(pec.frameSizes, resourceManager.GetTextureSize(config.Texture))
+	}
+	for i := range config.Frames {
+		frame := config.Frames[i]
+		pec.sprites = append(pec.sprites, render.NewSprite(config.Texture, &frame, false))
+		pec.frameSizes = append(pec.frameSizes, mgl32.Vec2{frame.W, frame.H})
+	}
+	for _, sprite := range pec.sprites {
+		sprite.SetBlendMode(config.BlendMode)
+	}
+	slog.Debug("create particle emitter component", slog.String("texture", config.Texture), slog.Float64("rate", float64(config.Rate)),
+		slog.Int("burst", config.Burst))
+	return pec
+}
+
+// 初始化
+func (pec *ParticleEmitterComponent) Init() {
+	if pec.Owner == nil {
+		slog.Error("particle emitter component owner is nil")
+		return
+	}
+	pec.transformComponent, _ = pec.Owner.GetComponent(def.ComponentTypeTransform).(*TransformComponent)
+	if pec.transformComponent == nil {
+		slog.Error("particle emitter component transform component is nil", slog.String("owner", pec.Owner.GetName()))
+		return
+	}
+}
+
+// 更新发射与粒子运动
+func (pec *ParticleEmitterComponent) Update(deltaTime float64, _ physics.IContext) {
+	if pec.transformComponent == nil {
+		return
+	}
+	if !pec.started {
+		pec.Start()
+	}
+
+	// 持续发射
+	if pec.isEmitting {
+		pec.elapsed += deltaTime
+		pec.emitAccumulator += float64(pec.config.Rate) * deltaTime
+		for pec.emitAccumulator >= 1.0 {
+			pec.emitAccumulator -= 1.0
+			pec.emit()
+		}
+		if pec.config.Rate <= 0.0 || (pec.config.Duration > 0.0 && pec.elapsed >= pec.config.Duration) {
+			pec.isEmitting = false
+		}
+	}
+
+	// 粒子运动，死亡的粒子与末尾交换后移除
+	dt := float32(deltaTime)
+	for i := 0; i < len(pec.particles); {
+		p := &pec.particles[i]
+		p.age += dt
+		if p.age >= p.lifetime {
+			pec.particles[i] = pec.particles[len(pec.particles)-1]
+			pec.particles = pec.particles[:len(pec.particles)-1]
+			continue
+		}
+		p.velocity = p.velocity.Add(p.gravity.Mul(dt))
+		p.position = p.position.Add(p.velocity.Mul(dt))
+		p.angle += p.angularVelocity * dt
+		i++
+	}
+
+	if pec.config.AutoRemove && pec.IsFinished() {
+		pec.Owner.SetNeedRemove(true)
+	}
+}
+
+// 渲染粒子
+func (pec *ParticleEmitterComponent) Render(context physics.IContext) {
+	if len(pec.particles) == 0 {
+		return
+	}
+	renderer := context.GetRenderer()
+	renderer.SetDrawOrder(pec.config.DrawLayer, 0.0)
+	defer renderer.ResetDrawOrder()
+	defer renderer.ResetColorMod()
+
+	for i := range pec.particles {
+		p := &pec.particles[i]
+		t := p.age / p.lifetime
+		renderer.SetColorMod(lerpColor(pec.config.StartColor, pec.config.EndColor, t))
+		scale := pec.config.StartScale + (pec.config.EndScale-pec.config.StartScale)*t
+		// 粒子位置为中心点，绘制位置为左上角
+		size := pec.frameSizes[p.frame].Mul(scale)
+		renderer.DrawSprite(context.GetCamera(), pec.sprites[p.frame], p.position.Sub(size.Mul(0.5)), mgl32.Vec2{scale, scale}, float64(p.angle))
+	}
+}
+
+// 发射一个粒子，超过最大数量时忽略
+func (pec *ParticleEmitterComponent) emit() {
+	if len(pec.particles) >= pec.config.MaxParticles {
+		return
+	}
+	spawn := mgl32.Vec2{
+		FloatRange{-pec.spawnArea.X() * 0.5, pec.spawnArea.X() * 0.5}.Random(),
+		FloatRange{-pec.spawnArea.Y() * 0.5, pec.spawnArea.Y() * 0.5}.Random(),
+	}
+	frame := 0
+	if len(pec.sprites) > 1 {
+		frame = rand.IntN(len(pec.sprites))
+	}
+	pec.particles = append(pec.particles, particle{
+		position:        pec.GetEmitPosition().Add(spawn),
+		velocity:        pec.config.Velocity.Random(),
+		gravity:         pec.config.Gravity.Random(),
+		angularVelocity: pec.config.AngularVelocity.Random(),
+		lifetime:        pec.config.Lifetime.Random(),
+		frame:           frame,
+	})
+}
+
+// 颜色线性插值
+func lerpColor(from, to emath.FColor, t float32) emath.FColor {
+	return emath.FColor{
+		R: from.R + (to.R-from.R)*t,
+		G: from.G + (to.G-from.G)*t,
+		B: from.B + (to.B-from.B)*t,
+		A: from.A + (to.A-from.A)*t,
+	}
+}
+
+// 开始发射：发射配置中的一次性粒子，有发射速率时开始持续发射
+func (pec *ParticleEmitterComponent) Start() {
+	pec.started = true
+	pec.elapsed = 0.0
+	pec.emitAccumulator = 0.0
+	pec.isEmitting = pec.config.Rate > 0.0
+	pec.Burst(pec.config.Burst)
+}
+
+// 停止持续发射，已发射的粒子继续运动直到寿命结束
+func (pec *ParticleEmitterComponent) Stop() {
+	pec.isEmitting = false
+}
+
+// 立即发射指定数量的粒子
+func (pec *ParticleEmitterComponent) Burst(count int) {
+	if pec.transformComponent == nil {
+		return
+	}
+	for range count {
+		pec.emit()
+	}
+}
+
+// 清除所有存活的粒子
+func (pec *ParticleEmitterComponent) Clear() {
+	pec.particles = pec.particles[:0]
+}
+
+// 是否正在持续发射
+func (pec *ParticleEmitterComponent) IsEmitting() bool {
+	return pec.isEmitting
+}
+
+// 是否已结束：不再发射且没有存活的粒子
+func (pec *ParticleEmitterComponent) IsFinished() bool {
+	return !pec.isEmitting && len(pec.particles) == 0
+}
+
+// 获取存活的粒子数量
+func (pec *ParticleEmitterComponent) GetParticleCount() int {
+	return len(pec.particles)
+}
+
+// 获取发射位置的世界坐标
+func (pec *ParticleEmitterComponent) GetEmitPosition() mgl32.Vec2 {
+	return pec.transformComponent.GetPosition().Add(pec.offset)
+}
+
+// 设置发射器相对于变换组件位置的偏移
+func (pec *ParticleEmitterComponent) SetOffset(offset mgl32.Vec2) {
+	pec.offset = offset
+}
+
+// 设置发射区域大小，以发射器位置为中心
+func (pec *ParticleEmitterComponent) SetSpawnArea(spawnArea mgl32.Vec2) {
+	pec.spawnArea = spawnArea
+}
+
+// 获取发射器配置
+func (pec *ParticleEmitterComponent) GetConfig() *ParticleEmitterConfig {
+	return pec.config
+}
@@ -0,0 +1,235 @@
+package component
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+
+	"sunny_land/src/engine/resource"
+	emath "sunny_land/src/engine/utils/math"
+
+	"github.com/SunshineZzzz/purego-sdl3/sdl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// 粒子发射器默认的最大粒子数量
+const defaultMaxParticles = 100
+
+// 配置中的混合模式名称
+var particleBlendModes = map[string]sdl.BlendMode{
+	"":      sdl.BlendModeBlend,
+	"blend": sdl.BlendModeBlend,
+	"add":   sdl.BlendModeAdd,
+	"mod":   sdl.BlendModeMod,
+	"mul":   sdl.BlendModeMul,
+}
+
+// 浮点数范围，每个粒子在范围内随机取值
+type FloatRange struct {
+	Min, Max float32
+}
+
+// 在范围内随机取值
+func (fr FloatRange) Random() float32 {
+	return fr.Min + (fr.Max-fr.Min)*rand.Float32()
+}
+
+// 二维向量范围，每个分量分别随机取值
+type Vec2Range struct {
+	Min, Max mgl32.Vec2
+}
+
+// 在范围内随机取值
+func (vr Vec2Range) Random() mgl32.Vec2 {
+	return mgl32.Vec2{
+		FloatRange{vr.Min.X(), vr.Max.X()}.Random(),
+		FloatRange{vr.Min.Y(), vr.Max.Y()}.Random(),
+	}
+}
+
+/**
+ * @brief 粒子发射器配置，可以由多个发射器共享。
+ *
+ * json格式(除texture外均可省略)：
+ * {
+ *   "texture": "assets/textures/FX/particles.png",
+ *   "frames": [[0, 0, 8, 8]],                          纹理中的帧(x, y, w, h)，每个粒子随机选择一帧，省略表示整张纹理
+ *   "rate": 20,                                        每秒发射数量，0表示只发射一次性的burst
+ *   "burst": 8,                                        开始时一次性发射的数量
+ *   "max_particles": 100,                              同时存在的最大粒子数量
+ *   "duration": 0,                                     持续发射的时长(秒)，0表示一直发射
+ *   "lifetime": [0.5, 1.0],                            粒子寿命范围(秒)
+ *   "velocity": {"min": [-10, -20], "max": [10, 0]},   初速度范围(像素/秒)
+ *   "gravity": {"min": [0, 100], "max": [0, 100]},     加速度范围(像素/秒^2)
+ *   "angular_velocity": [-90, 90],                     角速度范围(度/秒)
+ *   "spawn_area": [16, 0],                             以发射器位置为中心的发射区域大小
+ *   "color": {"start": [1, 1, 1, 1], "end": [1, 1, 1, 0]}, 颜色与不透明度随寿命变化
+ *   "scale": {"start": 1.0, "end": 0.5},               缩放随寿命变化
+ *   "draw_layer": 0,                                   绘制图层
+ *   "blend_mode": "blend",                             混合模式：blend(默认)、add(发光)、mod、mul
+ *   "auto_remove": false                               发射结束且粒子全部消失后移除所属的游戏对象
+ * }
+ */
+type ParticleEmitterConfig struct {
+	// 纹理路径
+	Texture string
+	// 纹理中的帧，空表示整张纹理
+	Frames []sdl.FRect
+	// 每秒发射数量
+	Rate float32
+	// 开始时一次性发射的数量
+	Burst int
+	// 同时存在的最大粒子数量
+	MaxParticles int
+	// 持续发射的时长(秒)，0表示一直发射
+	Duration float64
+	// 粒子寿命范围(秒)
+	Lifetime FloatRange
+	// 初速度范围
+	Velocity Vec2Range
+	// 加速度范围
+	Gravity Vec2Range
+	// 角速度范围(度/秒)
+	AngularVelocity FloatRange
+	// 发射区域大小，以发射器位置为中心
+	SpawnArea mgl32.Vec2
+	// 出生与消失时的颜色
+	StartColor, EndColor emath.FColor
+	// 出生与消失时的缩放
+	StartScale, EndScale float32
+	// 绘制图层
+	DrawLayer int
+	// 混合模式
+	BlendMode sdl.BlendMode
+	// 发射结束且粒子全部消失后移除所属的游戏对象
+	AutoRemove bool
+}
+
+// 粒子发射器json中的范围
+type vec2RangeJson struct {
+	Min [2]float32 `json:"min"`
+	Max [2]float32 `json:"max"`
+}
+
+// 粒子发射器json的根结构
+type particleEmitterJson struct {
+	Texture         string         `json:"texture"`
+	Frames          [][4]float32   `json:"frames"`
+	Rate            float32        `json:"rate"`
+	Burst           int            `json:"burst"`
+	MaxParticles    int            `json:"max_particles"`
+	Duration        float64        `json:"duration"`
+	Lifetime        *[2]float32    `json:"lifetime"`
+	Velocity        *vec2RangeJson `json:"velocity"`
+	Gravity         *vec2RangeJson `json:"gravity"`
+	AngularVelocity *[2]float32    `json:"angular_velocity"`
+	SpawnArea       [2]float32     `json:"spawn_area"`
+	Color           *struct {
+		Start [4]float32 `json:"start"`
+		End   [4]float32 `json:"end"`
+	} `json:"color"`
+	Scale *struct {
+		Start float32 `json:"start"`
+		End   float32 `json:"end"`
+	} `json:"scale"`
+	DrawLayer  int    `json:"draw_layer"`
+	BlendMode  string `json:"blend_mode"`
+	AutoRemove bool   `json:"auto_remove"`
+}
+
+/**
+ * @brief 通过虚拟文件系统加载粒子发射器配置
+ * @param path json文件路径
+ * @return *ParticleEmitterConfig 配置，失败返回nil
+ */
+func LoadParticleEmitterConfig(path string) *ParticleEmitterConfig {
+	data, err := resource.GetVFS().ReadFile(path)
+	if err != nil {
+		slog.Error("read particle emitter json failed", slog.String("path", path), slog.String("error", err.Error()))
+		return nil
+	}
+	config, err := ParseParticleEmitterConfig(data)
+	if err != nil {
+		slog.Error("parse particle emitter json failed", slog.String("path", path), slog.String("error", err.Error()))
+		return nil
+	}
+	return config
+}
+
+/**
+ * @brief 解析粒子发射器配置
+ * @param data json数据
+ * @return *ParticleEmitterConfig 配置
+ * @return error 错误
+ */
+func ParseParticleEmitterConfig(data []byte) (*ParticleEmitterConfig, error) {
+	var raw particleEmitterJson
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	if raw.Texture == "" {
+		return nil, fmt.Errorf("particle emitter has no texture")
+	}
+	if raw.Rate < 0.0 || raw.Burst < 0 {
+		return nil, fmt.Errorf("particle emitter rate and burst must not be negative")
+	}
+	if raw.Rate == 0.0 && raw.Burst == 0 {
+		return nil, fmt.Errorf("particle emitter emits nothing, set rate or burst")
+	}
+
+	config := &ParticleEmitterConfig{
+		Texture:      raw.Texture,
+		Frames:       make([]sdl.FRect, 0, len(raw.Frames)),
+		Rate:         raw.Rate,
+		Burst:        raw.Burst,
+		MaxParticles: raw.MaxParticles,
+		Duration:     raw.Duration,
+		Lifetime:     FloatRange{1.0, 1.0},
+		SpawnArea:    mgl32.Vec2{raw.SpawnArea[0], raw.SpawnArea[1]},
+		StartColor:   emath.FColor{R: 1.0, G: 1.0, B: 1.0, A: 1.0},
+		EndColor:     emath.FColor{R: 1.0, G: 1.0, B: 1.0, A: 1.0},
+		StartScale:   1.0,
+		EndScale:     1.0,
+		DrawLayer:    raw.DrawLayer,
+		AutoRemove:   raw.AutoRemove,
+	}
+	blendMode, ok := particleBlendModes[raw.BlendMode]
+	if !ok {
+		return nil, fmt.Errorf("unknown particle blend mode %q", raw.BlendMode)
+	}
+	config.BlendMode = blendMode
+	if config.MaxParticles <= 0 {
+		config.MaxParticles = defaultMaxParticles
+	}
+	for i, frame := range raw.Frames {
+		if frame[2] <= 0.0 || frame[3] <= 0.0 {
+			return nil, fmt.Errorf("particle frame %d has no size", i)
+		}
+		config.Frames = append(config.Frames, sdl.FRect{X: frame[0], Y: frame[1], W: frame[2], H: frame[3]})
+	}
+	if raw.Lifetime != nil {
+		config.Lifetime = FloatRange{raw.Lifetime[0], raw.Lifetime[1]}
+	}
+	if config.Lifetime.Min <= 0.0 || config.Lifetime.Max < config.Lifetime.Min {
+		return nil, fmt.Errorf("particle lifetime [%g, %g] is invalid", config.Lifetime.Min, config.Lifetime.Max)
+	}
+	if raw.Velocity != nil {
+		config.Velocity = Vec2Range{mgl32.Vec2(raw.Velocity.Min), mgl32.Vec2(raw.Velocity.Max)}
+	}
+	if raw.Gravity != nil {
+		config.Gravity = Vec2Range{mgl32.Vec2(raw.Gravity.Min), mgl32.Vec2(raw.Gravity.Max)}
+	}
+	if raw.AngularVelocity != nil {
+		config.AngularVelocity = FloatRange{raw.AngularVelocity[0], raw.AngularVelocity[1]}
+	}
+	if raw.Color != nil {
+		config.StartColor = emath.FColor{R: raw.Color.Start[0], G: raw.Color.Start[1], B: raw.Color.Start[2], A: raw.Color.Start[3]}
+		config.EndColor = emath.FColor{R: raw.Color.End[0], G: raw.Color.End[1], B: raw.Color.End[2], A: raw.Color.End[3]}
+	}
+	if raw.Scale != nil {
+		config.StartScale = raw.Scale.Start
+		config.EndScale = raw.Scale.End
+	}
+	return config, nil
+}
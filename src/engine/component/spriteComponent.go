@@ -30,8 +30,16 @@ type SpriteComponent struct {
 	spriteSize mgl32.Vec2
 	// 偏移量
 	offset mgl32.Vec2
+	// 裁剪帧在原始帧中的偏移(未缩放)，未裁剪为0
+	trimOffset mgl32.Vec2
+	// 裁剪帧的原始尺寸，未裁剪为0，对齐按原始尺寸计算
+	untrimmedSize mgl32.Vec2
 	// 是否隐藏
 	isHidden bool
+	// 绘制图层，越大越靠前
+	drawLayer int
+	// 图层内的排序键，越大越靠前
+	sortKey float32
 }
 
 // 确保SpriteComponent实现了IComponent接口
@@ -105,7 +113,9 @@ func (sc *SpriteComponent) updateSpriteSize() {
 		return
 	}
 
-	if sc.sprite.GetSourceRect() != nil {
+	if sc.untrimmedSize.X() > 0.0 && sc.untrimmedSize.Y() > 0.0 {
+		sc.spriteSize = sc.untrimmedSize
+	} else if sc.sprite.GetSourceRect() != nil {
 		sc.spriteSize = mgl32.Vec2{sc.sprite.GetSourceRect().W, sc.sprite.GetSourceRect().H}
 	} else {
 		textureSize := sc.resourceManager.GetTextureSize(sc.sprite.GetTextureId())
@@ -154,6 +164,17 @@ func (sc *SpriteComponent) SetAlignment(anchor utils.Alignment) {
 // 设置源矩形
 func (sc *SpriteComponent) SetSourceRect(sourceRect *sdl.FRect) {
 	sc.sprite.SetSourceRect(sourceRect)
+	sc.trimOffset = mgl32.Vec2{}
+	sc.untrimmedSize = mgl32.Vec2{}
+	sc.updateSpriteSize()
+	sc.updateOffset()
+}
+
+// 设置动画帧，裁剪过的帧按原始帧尺寸对齐，并补偿裁剪偏移
+func (sc *SpriteComponent) SetFrame(frame *physics.AnimationFrame) {
+	sc.sprite.SetSourceRect(frame.SourceRect)
+	sc.trimOffset = frame.Offset
+	sc.untrimmedSize = frame.SourceSize
 	sc.updateSpriteSize()
 	sc.updateOffset()
 }
@@ -162,6 +183,8 @@ func (sc *SpriteComponent) SetSourceRect(sourceRect *sdl.FRect) {
 func (sc *SpriteComponent) SetSpriteById(textureId string, sourceRect *sdl.FRect) {
 	sc.sprite.SetTextureId(textureId)
 	sc.sprite.SetSourceRect(sourceRect)
+	sc.trimOffset = mgl32.Vec2{}
+	sc.untrimmedSize = mgl32.Vec2{}
 
 	sc.updateSpriteSize()
 	sc.updateOffset()
@@ -176,10 +199,43 @@ func (sc *SpriteComponent) Render(context physics.IContext) {
 	// 获取变换信息，并且考虑偏移量
 	transform := sc.transformComponent.GetPosition().Add(sc.offset)
 	scale := sc.transformComponent.GetScale()
+	if sc.untrimmedSize.X() > 0.0 {
+		transform = transform.Add(emath.Mgl32Vec2MulElem(sc.getTrimOffset(), scale))
+	}
 	rotationDegrees := sc.transformComponent.GetRotation()
 
 	// 执行绘制
+	context.GetRenderer().SetDrawOrder(sc.drawLayer, sc.sortKey)
 	context.GetRenderer().DrawSprite(context.GetCamera(), sc.sprite, transform, scale, rotationDegrees)
+	context.GetRenderer().ResetDrawOrder()
+}
+
+/**
+ * @brief 设置绘制顺序，图层相同时按排序键，再相同时按游戏对象在场景中的顺序
+ * @param layer 绘制图层，越大越靠前
+ * @param sortKey 图层内的排序键，越大越靠前
+ */
+func (sc *SpriteComponent) SetDrawOrder(layer int, sortKey float32) {
+	sc.drawLayer = layer
+	sc.sortKey = sortKey
+}
+
+// 获取绘制图层
+func (sc *SpriteComponent) GetDrawLayer() int {
+	return sc.drawLayer
+}
+
+// 获取图层内的排序键
+func (sc *SpriteComponent) GetSortKey() float32 {
+	return sc.sortKey
+}
+
+// 获取裁剪偏移，水平反转时从右侧计算
+func (sc *SpriteComponent) getTrimOffset() mgl32.Vec2 {
+	if !sc.sprite.GetIsFlipped() || sc.sprite.GetSourceRect() == nil {
+		return sc.trimOffset
+	}
+	return mgl32.Vec2{sc.untrimmedSize.X() - sc.trimOffset.X() - sc.sprite.GetSourceRect().W, sc.trimOffset.Y()}
 }
 
 // 更新组件状态
@@ -200,3 +256,33 @@ func (sc *SpriteComponent) GetIsFliped() bool {
 func (sc *SpriteComponent) SetHidden(isHidden bool) {
 	sc.isHidden = isHidden
 }
+
+// 设置颜色调制，RGB为色调，A为不透明度，例如受击时染红
+func (sc *SpriteComponent) SetColor(color emath.FColor) {
+	sc.sprite.SetColor(color)
+}
+
+// 获取颜色调制
+func (sc *SpriteComponent) GetColor() emath.FColor {
+	return sc.sprite.GetColor()
+}
+
+// 设置不透明度，0.0完全透明，1.0不透明，例如淡出或无敌闪烁
+func (sc *SpriteComponent) SetAlpha(alpha float32) {
+	sc.sprite.SetAlpha(alpha)
+}
+
+// 获取不透明度
+func (sc *SpriteComponent) GetAlpha() float32 {
+	return sc.sprite.GetAlpha()
+}
+
+// 设置混合模式，例如sdl.BlendModeAdd用于发光效果
+func (sc *SpriteComponent) SetBlendMode(blendMode sdl.BlendMode) {
+	sc.sprite.SetBlendMode(blendMode)
+}
+
+// 获取混合模式
+func (sc *SpriteComponent) GetBlendMode() sdl.BlendMode {
+	return sc.sprite.GetBlendMode()
+}
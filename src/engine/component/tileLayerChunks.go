@@ -0,0 +1,247 @@
+package component
+
+import (
+	"log/slog"
+	"math"
+
+	"sunny_land/src/engine/physics"
+
+	"github.com/SunshineZzzz/purego-sdl3/sdl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// 区块的最大像素尺寸
+const tileChunkSize = 512
+
+// 瓦片层区块，预先绘制了一块区域内所有瓦片的渲染目标纹理
+type tileChunk struct {
+	// 渲染目标纹理，区块内没有瓦片时为nil
+	texture *sdl.Texture
+	// 是否需要重新绘制
+	dirty bool
+}
+
+/**
+ * @brief 瓦片层区块缓存。
+ *
+ * 将瓦片层划分为不超过512x512像素的区块，每个区块预先绘制到一张渲染目标纹理中，
+ * 渲染时只绘制与视口相交的区块，每个区块一次绘制调用。区块在瓦片变化或纹理被重新加载时重绘。
+ * 比瓦片格子高(向上延伸)或宽(向右延伸)的图片绘制在其所在的区块中，区块纹理为此额外留出空间。
+ * 瓦片以普通混合绘制到透明的区块纹理后颜色为预乘alpha，区块以预乘alpha混合合成(见Renderer.DrawTexture)，
+ * 半透明的瓦片边缘与图层不透明度与不使用区块时一致。
+ */
+type tileChunkCache struct {
+	// 每个区块包含的瓦片数量
+	tilesPerChunkX, tilesPerChunkY int
+	// 区块数量
+	countX, countY int
+	// 区块的像素尺寸(不含溢出部分)
+	chunkSize mgl32.Vec2
+	// 图片超出瓦片格子的最大像素，X向右，Y向上
+	overflow mgl32.Vec2
+	// 区块，按行主序存储
+	chunks []tileChunk
+	// 绘制区块时的纹理重新加载次数
+	textureGeneration uint64
+}
+
+// 创建区块缓存，所有区块初始为待绘制
+func (tlc *TileLayerComponent) newChunkCache(textureGeneration uint64) *tileChunkCache {
+	cache := &tileChunkCache{
+		tilesPerChunkX:    max(1, int(tileChunkSize/tlc.tileSize.X())),
+		tilesPerChunkY:    max(1, int(tileChunkSize/tlc.tileSize.Y())),
+		overflow:          tlc.getTileOverflow(),
+		textureGeneration: textureGeneration,
+	}
+	cache.chunkSize = mgl32.Vec2{
+		float32(cache.tilesPerChunkX) * tlc.tileSize.X(),
+		float32(cache.tilesPerChunkY) * tlc.tileSize.Y(),
+	}
+	cache.countX = (int(tlc.mapSize.X()) + cache.tilesPerChunkX - 1) / cache.tilesPerChunkX
+	cache.countY = (int(tlc.mapSize.Y()) + cache.tilesPerChunkY - 1) / cache.tilesPerChunkY
+	cache.chunks = make([]tileChunk, cache.countX*cache.countY)
+	for i := range cache.chunks {
+		cache.chunks[i].dirty = true
+	}
+	slog.Debug("tile chunk cache created", slog.Int("countX", cache.countX), slog.Int("countY", cache.countY), slog.Any("overflow", cache.overflow))
+	return cache
+}
+
+// 计算图片超出瓦片格子的最大像素
+func (tlc *TileLayerComponent) getTileOverflow() mgl32.Vec2 {
+	overflow := mgl32.Vec2{0.0, 0.0}
+	for _, tileInfo := range tlc.tiles {
+		if tileInfo == nil || tileInfo.Sprite == nil || tileInfo.Sprite.GetSourceRect() == nil {
+			continue
+		}
+		rect := tileInfo.Sprite.GetSourceRect()
+		overflow[0] = max(overflow.X(), rect.W-tlc.tileSize.X())
+		overflow[1] = max(overflow.Y(), rect.H-tlc.tileSize.Y())
+	}
+	return overflow
+}
+
+/**
+ * @brief 绘制与视口相交的区块，需要时先重绘区块
+ * @param context 上下文
+ * @param offset 图层原点的世界坐标(含视差偏移)
+ * @return bool 是否成功，失败时调用者逐个瓦片绘制
+ */
+func (tlc *TileLayerComponent) renderChunks(context physics.IContext, offset mgl32.Vec2) bool {
+	renderer := context.GetRenderer()
+	if tlc.chunks == nil {
+		tlc.chunks = tlc.newChunkCache(renderer.GetTextureGeneration())
+	}
+	cache := tlc.chunks
+	if generation := renderer.GetTextureGeneration(); generation != cache.textureGeneration {
+		// 纹理被重新加载，区块中的内容已经过期
+		cache.textureGeneration = generation
+		tlc.InvalidateChunks()
+	}
+
+	// 视口在图层坐标系中的范围，考虑溢出部分
+	camera := context.GetCamera()
+	viewMin := camera.GetPosition().Sub(offset)
+	viewMax := viewMin.Add(camera.GetViewSize())
+	minX := max(0, int(math.Floor(float64((viewMin.X()-cache.overflow.X())/cache.chunkSize.X()))))
+	maxX := min(cache.countX-1, int(math.Floor(float64(viewMax.X()/cache.chunkSize.X()))))
+	minY := max(0, int(math.Floor(float64(viewMin.Y()/cache.chunkSize.Y()))))
+	maxY := min(cache.countY-1, int(math.Floor(float64((viewMax.Y()+cache.overflow.Y())/cache.chunkSize.Y()))))
+
+	// 先绘制所有需要的区块，任何一个失败都退回逐个瓦片绘制，避免重复绘制
+	for cy := minY; cy <= maxY; cy++ {
+		for cx := minX; cx <= maxX; cx++ {
+			if !tlc.bakeChunk(renderer, cx, cy) {
+				return false
+			}
+		}
+	}
+	for cy := minY; cy <= maxY; cy++ {
+		for cx := minX; cx <= maxX; cx++ {
+			chunk := &cache.chunks[cy*cache.countX+cx]
+			if chunk.texture == nil {
+				continue
+			}
+			var w, h float32
+			sdl.GetTextureSize(chunk.texture, &w, &h)
+			position := offset.Add(mgl32.Vec2{
+				float32(cx) * cache.chunkSize.X(),
+				float32(cy)*cache.chunkSize.Y() - cache.overflow.Y(),
+			})
+			renderer.DrawTexture(camera, chunk.texture, position, mgl32.Vec2{w, h})
+		}
+	}
+	return true
+}
+
+// 重绘区块，区块不需要重绘时直接返回
+func (tlc *TileLayerComponent) bakeChunk(renderer physics.IRenderer, cx, cy int) bool {
+	cache := tlc.chunks
+	chunk := &cache.chunks[cy*cache.countX+cx]
+	if !chunk.dirty {
+		return true
+	}
+
+	// 区块内的瓦片范围，最后一行(列)的区块可能不满
+	startX, startY := cx*cache.tilesPerChunkX, cy*cache.tilesPerChunkY
+	endX := min(startX+cache.tilesPerChunkX, int(tlc.mapSize.X()))
+	endY := min(startY+cache.tilesPerChunkY, int(tlc.mapSize.Y()))
+	hasTile := false
+	for y := startY; y < endY && !hasTile; y++ {
+		for x := startX; x < endX; x++ {
+			if tlc.getRenderableTile(x, y) != nil {
+				hasTile = true
+				break
+			}
+		}
+	}
+	if !hasTile {
+		// 空区块不需要纹理
+		if chunk.texture != nil {
+			sdl.DestroyTexture(chunk.texture)
+			chunk.texture = nil
+		}
+		chunk.dirty = false
+		return true
+	}
+
+	if chunk.texture == nil {
+		w := int32(math.Ceil(float64(float32(endX-startX)*tlc.tileSize.X() + cache.overflow.X())))
+		h := int32(math.Ceil(float64(float32(endY-startY)*tlc.tileSize.Y() + cache.overflow.Y())))
+		chunk.texture = renderer.CreateRenderTarget(w, h)
+		if chunk.texture == nil {
+			return false
+		}
+	}
+
+	// 纹理左上角在图层坐标系中的位置
+	origin := mgl32.Vec2{float32(startX) * tlc.tileSize.X(), float32(startY)*tlc.tileSize.Y() - cache.overflow.Y()}
+	ok := renderer.RenderToTexture(chunk.texture, func() {
+		for y := startY; y < endY; y++ {
+			for x := startX; x < endX; x++ {
+				tileInfo := tlc.getRenderableTile(x, y)
+				if tileInfo == nil {
+					continue
+				}
+				renderer.DrawUISprite(tileInfo.Sprite, tlc.getTileDrawPosition(x, y, tileInfo).Sub(origin), nil)
+			}
+		}
+	})
+	if !ok {
+		return false
+	}
+	chunk.dirty = false
+	return true
+}
+
+// 标记所有区块需要重绘
+func (tlc *TileLayerComponent) InvalidateChunks() {
+	if tlc.chunks == nil {
+		return
+	}
+	for i := range tlc.chunks.chunks {
+		tlc.chunks.chunks[i].dirty = true
+	}
+}
+
+// 标记瓦片所在的区块需要重绘，图片超出原有的溢出范围时重建整个缓存
+func (tlc *TileLayerComponent) invalidateChunkAt(x, y int) {
+	cache := tlc.chunks
+	if cache == nil {
+		return
+	}
+	if tileInfo := tlc.getRenderableTile(x, y); tileInfo != nil && tileInfo.Sprite.GetSourceRect() != nil {
+		rect := tileInfo.Sprite.GetSourceRect()
+		if rect.W-tlc.tileSize.X() > cache.overflow.X() || rect.H-tlc.tileSize.Y() > cache.overflow.Y() {
+			tlc.destroyChunks()
+			return
+		}
+	}
+	cache.chunks[(y/cache.tilesPerChunkY)*cache.countX+x/cache.tilesPerChunkX].dirty = true
+}
+
+// 销毁区块缓存，下次渲染时重建
+func (tlc *TileLayerComponent) destroyChunks() {
+	if tlc.chunks == nil {
+		return
+	}
+	for _, chunk := range tlc.chunks.chunks {
+		if chunk.texture != nil {
+			sdl.DestroyTexture(chunk.texture)
+		}
+	}
+	tlc.chunks = nil
+}
+
+// 设置是否使用预先绘制的区块渲染，瓦片经常变化的图层可以关闭
+func (tlc *TileLayerComponent) SetCached(isCached bool) {
+	tlc.isCached = isCached
+	if !isCached {
+		tlc.destroyChunks()
+	}
+}
+
+// 是否使用预先绘制的区块渲染
+func (tlc *TileLayerComponent) IsCached() bool {
+	return tlc.isCached
+}
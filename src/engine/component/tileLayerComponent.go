@@ -6,6 +6,7 @@ import (
 
 	"sunny_land/src/engine/physics"
 	"sunny_land/src/engine/utils/def"
+	emath "sunny_land/src/engine/utils/math"
 
 	"github.com/go-gl/mathgl/mgl32"
 )
@@ -20,15 +21,42 @@ type TileLayerComponent struct {
 	mapSize mgl32.Vec2
 	// 存储所有瓦片信息，按行主序存储，index = y * mapSize.X + x
 	tiles []*physics.TileInfo
-	// 瓦片层在世界中的偏移量，瓦片层通常不需要缩放及旋转，因此不引入Transform组件
-	// offset 最好也保持默认的0，以免增加不必要的复杂性
+	// 瓦片层在世界中的偏移量(Tiled中的offsetx/offsety)，瓦片层通常不需要缩放及旋转，因此不引入Transform组件
+	// 物理引擎检测碰撞时同样减去偏移量，绘制位置与碰撞位置一致
 	offset mgl32.Vec2
 	// 是否隐藏
 	isHidden bool
+	// 视差滚动因子，1.0=随相机移动(默认)，<1.0=比相机慢
+	scrollFactor mgl32.Vec2
+	// 颜色调制，RGB为色调，A为不透明度
+	color emath.FColor
 	// 物理引擎
 	physicsEngine *physics.PhysicsEngine
+	// 绘制图层，越大越靠前
+	drawLayer int
+	// 是否使用预先绘制的区块渲染
+	isCached bool
+	// 区块缓存，首次渲染时创建
+	chunks *tileChunkCache
+	// 瓦片变化监听函数
+	tileChangedListeners []TileChangedListener
 }
 
+// 瓦片变化事件
+type TileChangedEvent struct {
+	// 发生变化的瓦片层
+	Layer *TileLayerComponent
+	// 瓦片坐标
+	X, Y int
+	// 变化前的瓦片信息
+	OldTile *physics.TileInfo
+	// 变化后的瓦片信息
+	NewTile *physics.TileInfo
+}
+
+// 瓦片变化监听函数
+type TileChangedListener func(event TileChangedEvent)
+
 // 确保TileLayerComponent实现了IComponent接口
 var _ physics.IComponent = (*TileLayerComponent)(nil)
 
@@ -42,11 +70,14 @@ func NewTileLayerComponent(tileSize mgl32.Vec2, mapSize mgl32.Vec2, tiles []*phy
 		Component: Component{
 			ComponentType: def.ComponentTypeTileLayer,
 		},
-		tileSize: tileSize,
-		mapSize:  mapSize,
-		tiles:    tiles,
-		offset:   mgl32.Vec2{0.0, 0.0},
-		isHidden: false,
+		tileSize:     tileSize,
+		mapSize:      mapSize,
+		tiles:        tiles,
+		offset:       mgl32.Vec2{0.0, 0.0},
+		isHidden:     false,
+		scrollFactor: mgl32.Vec2{1.0, 1.0},
+		color:        emath.FColor{R: 1.0, G: 1.0, B: 1.0, A: 1.0},
+		isCached:     true,
 	}
 }
 
@@ -61,36 +92,58 @@ func (tlc *TileLayerComponent) Init() {
 
 // 渲染
 func (tlc *TileLayerComponent) Render(context physics.IContext) {
-	if tlc.isHidden || tlc.tileSize.X() <= 0.0 || tlc.tileSize.Y() <= 0.0 {
+	if tlc.isHidden || tlc.tileSize.X() <= 0.0 || tlc.tileSize.Y() <= 0.0 || tlc.color.A <= 0.0 {
+		return
+	}
+
+	// 设置颜色调制及绘制图层，绘制完毕后重置
+	context.GetRenderer().SetColorMod(tlc.color)
+	defer context.GetRenderer().ResetColorMod()
+	context.GetRenderer().SetDrawOrder(tlc.drawLayer, 0.0)
+	defer context.GetRenderer().ResetDrawOrder()
+
+	// 视差偏移：DrawSprite按相机位置完整平移，这里补偿(1 - scrollFactor)部分
+	parallaxOffset := emath.Mgl32Vec2MulElem(context.GetCamera().GetPosition(), mgl32.Vec2{1.0 - tlc.scrollFactor.X(), 1.0 - tlc.scrollFactor.Y()})
+	offset := tlc.offset.Add(parallaxOffset)
+
+	// 静态瓦片层使用预先绘制的区块
+	if tlc.isCached && tlc.renderChunks(context, offset) {
 		return
 	}
 
 	// 遍历所有瓦片
 	for y := 0; y < int(tlc.mapSize.Y()); y++ {
 		for x := 0; x < int(tlc.mapSize.X()); x++ {
-			// 获取索引
-			index := y*int(tlc.mapSize.X()) + x
-			// 检查索引有效性和瓦片是否需要渲染
-			if index >= len(tlc.tiles) || tlc.tiles[index].Type == physics.TileTypeEmpty {
+			tileInfo := tlc.getRenderableTile(x, y)
+			if tileInfo == nil {
 				continue
 			}
-			tileInfo := tlc.tiles[index]
-			// 计算该瓦片在世界中左上角位置
-			leftTopPos := mgl32.Vec2{
-				tlc.offset.X() + float32(x)*tlc.tileSize.X(),
-				tlc.offset.Y() + float32(y)*tlc.tileSize.Y(),
-			}
-			// 如果图片大小和瓦片大小不一致，需要调整Y坐标
-			if tileInfo.Sprite.GetSourceRect().H != tlc.tileSize.Y() {
-				// 目的就是让图片从左下角往上绘制
-				leftTopPos[1] -= (tileInfo.Sprite.GetSourceRect().H - tlc.tileSize.Y())
-			}
 			// 执行绘制
-			context.GetRenderer().DrawSprite(context.GetCamera(), tileInfo.Sprite, leftTopPos, mgl32.Vec2{1.0, 1.0}, 0.0)
+			context.GetRenderer().DrawSprite(context.GetCamera(), tileInfo.Sprite, offset.Add(tlc.getTileDrawPosition(x, y, tileInfo)), mgl32.Vec2{1.0, 1.0}, 0.0)
 		}
 	}
 }
 
+// 获取需要绘制的瓦片，空瓦片返回nil
+func (tlc *TileLayerComponent) getRenderableTile(x, y int) *physics.TileInfo {
+	index := y*int(tlc.mapSize.X()) + x
+	if index >= len(tlc.tiles) || tlc.tiles[index] == nil || tlc.tiles[index].Type == physics.TileTypeEmpty || tlc.tiles[index].Sprite == nil {
+		return nil
+	}
+	return tlc.tiles[index]
+}
+
+// 获取瓦片绘制的左上角位置，相对于图层原点
+func (tlc *TileLayerComponent) getTileDrawPosition(x, y int, tileInfo *physics.TileInfo) mgl32.Vec2 {
+	position := mgl32.Vec2{float32(x) * tlc.tileSize.X(), float32(y) * tlc.tileSize.Y()}
+	// 如果图片大小和瓦片大小不一致，需要调整Y坐标
+	if tileInfo.Sprite.GetSourceRect().H != tlc.tileSize.Y() {
+		// 目的就是让图片从左下角往上绘制
+		position[1] -= (tileInfo.Sprite.GetSourceRect().H - tlc.tileSize.Y())
+	}
+	return position
+}
+
 // 获取指定位置的瓦片信息
 func (tlc *TileLayerComponent) GetTileInfoAt(posX, posY int) *physics.TileInfo {
 	if posX < 0 || posX >= int(tlc.mapSize.X()) || posY < 0 || posY >= int(tlc.mapSize.Y()) {
@@ -106,6 +159,66 @@ func (tlc *TileLayerComponent) GetTileInfoAt(posX, posY int) *physics.TileInfo {
 	return tlc.tiles[index]
 }
 
+/**
+ * @brief 设置指定位置的瓦片，渲染与物理(物理引擎直接查询瓦片类型)同时生效，并通知监听函数
+ * @param posX 瓦片坐标X
+ * @param posY 瓦片坐标Y
+ * @param tileInfo 新的瓦片信息，nil等同于ClearTileAt
+ * @return bool 是否成功
+ */
+func (tlc *TileLayerComponent) SetTileAt(posX, posY int, tileInfo *physics.TileInfo) bool {
+	if tileInfo == nil {
+		tileInfo = &physics.TileInfo{Type: physics.TileTypeEmpty}
+	}
+	if tileInfo.Type != physics.TileTypeEmpty && (tileInfo.Sprite == nil || tileInfo.Sprite.GetSourceRect() == nil) {
+		slog.Error("tile sprite or source rect is nil", slog.Int("posX", posX), slog.Int("posY", posY))
+		return false
+	}
+	oldTile := tlc.GetTileInfoAt(posX, posY)
+	if oldTile == nil {
+		return false
+	}
+
+	tlc.tiles[posY*int(tlc.mapSize.X())+posX] = tileInfo
+	tlc.invalidateChunkAt(posX, posY)
+
+	event := TileChangedEvent{Layer: tlc, X: posX, Y: posY, OldTile: oldTile, NewTile: tileInfo}
+	for _, listener := range tlc.tileChangedListeners {
+		listener(event)
+	}
+	slog.Debug("tile changed", slog.Int("posX", posX), slog.Int("posY", posY), slog.Any("oldType", oldTile.Type), slog.Any("newType", tileInfo.Type))
+	return true
+}
+
+// 清除指定位置的瓦片，之后该位置为空瓦片(不渲染、无碰撞)
+func (tlc *TileLayerComponent) ClearTileAt(posX, posY int) bool {
+	return tlc.SetTileAt(posX, posY, nil)
+}
+
+// 设置指定世界位置的瓦片
+func (tlc *TileLayerComponent) SetTileAtWorldPos(posXF, posYF float32, tileInfo *physics.TileInfo) bool {
+	posX, posY := tlc.WorldToTile(posXF, posYF)
+	return tlc.SetTileAt(posX, posY, tileInfo)
+}
+
+// 清除指定世界位置的瓦片
+func (tlc *TileLayerComponent) ClearTileAtWorldPos(posXF, posYF float32) bool {
+	posX, posY := tlc.WorldToTile(posXF, posYF)
+	return tlc.ClearTileAt(posX, posY)
+}
+
+// 世界位置转换为瓦片坐标，需要减去图层偏移量
+func (tlc *TileLayerComponent) WorldToTile(posXF, posYF float32) (int, int) {
+	posX := int(math.Floor(float64(posXF-tlc.offset.X()) / float64(tlc.tileSize.X())))
+	posY := int(math.Floor(float64(posYF-tlc.offset.Y()) / float64(tlc.tileSize.Y())))
+	return posX, posY
+}
+
+// 添加瓦片变化监听函数，瓦片通过SetTileAt/ClearTileAt变化时调用
+func (tlc *TileLayerComponent) AddTileChangedListener(listener TileChangedListener) {
+	tlc.tileChangedListeners = append(tlc.tileChangedListeners, listener)
+}
+
 // 获取指定位置的瓦片类型，pos不是整数坐标
 func (tlc *TileLayerComponent) GetTileTypeAt(posX, posY int) physics.TileType {
 	tileInfo := tlc.GetTileInfoAt(posX, posY)
@@ -118,8 +231,7 @@ func (tlc *TileLayerComponent) GetTileTypeAt(posX, posY int) physics.TileType {
 // 获取指定世界位置的瓦片类型
 func (tlc *TileLayerComponent) GetTileTypeAtWorldPos(posXF, posYF float32) physics.TileType {
 	// 先将世界位置转换为瓦片位置
-	posX := int(math.Floor(float64(posXF) / float64(tlc.tileSize.X())))
-	posY := int(math.Floor(float64(posYF) / float64(tlc.tileSize.Y())))
+	posX, posY := tlc.WorldToTile(posXF, posYF)
 	return tlc.GetTileTypeAt(posX, posY)
 }
 
@@ -134,6 +246,8 @@ func (tlc *TileLayerComponent) Clean() {
 		tlc.physicsEngine.UnregisterTileLayerComponent(tlc)
 		tlc.physicsEngine = nil
 	}
+	tlc.destroyChunks()
+	tlc.tileChangedListeners = nil
 }
 
 // 获取瓦片大小
@@ -145,3 +259,48 @@ func (tlc *TileLayerComponent) GetTileSize() mgl32.Vec2 {
 func (tlc *TileLayerComponent) GetWorldSize() mgl32.Vec2 {
 	return mgl32.Vec2{tlc.mapSize.X() * tlc.tileSize.X(), tlc.mapSize.Y() * tlc.tileSize.Y()}
 }
+
+// 设置瓦片层在世界中的偏移量
+func (tlc *TileLayerComponent) SetOffset(offset mgl32.Vec2) {
+	tlc.offset = offset
+}
+
+// 获取瓦片层在世界中的偏移量
+func (tlc *TileLayerComponent) GetOffset() mgl32.Vec2 {
+	return tlc.offset
+}
+
+// 设置视差滚动因子
+func (tlc *TileLayerComponent) SetScrollFactor(scrollFactor mgl32.Vec2) {
+	tlc.scrollFactor = scrollFactor
+}
+
+// 获取视差滚动因子
+func (tlc *TileLayerComponent) GetScrollFactor() mgl32.Vec2 {
+	return tlc.scrollFactor
+}
+
+// 设置绘制图层，越大越靠前
+func (tlc *TileLayerComponent) SetDrawLayer(layer int) {
+	tlc.drawLayer = layer
+}
+
+// 设置颜色调制，RGB为色调，A为不透明度
+func (tlc *TileLayerComponent) SetColor(color emath.FColor) {
+	tlc.color = color
+}
+
+// 获取颜色调制
+func (tlc *TileLayerComponent) GetColor() emath.FColor {
+	return tlc.color
+}
+
+// 设置是否隐藏，不渲染
+func (tlc *TileLayerComponent) SetHidden(isHidden bool) {
+	tlc.isHidden = isHidden
+}
+
+// 获取是否隐藏
+func (tlc *TileLayerComponent) GetHidden() bool {
+	return tlc.isHidden
+}
@@ -13,6 +13,7 @@ type configJson struct {
 	Performance   performanceConfig   `json:"performance"`
 	Audio         audioConfig         `json:"audio"`
 	InputMappings map[string][]string `json:"input_mappings"`
+	Debug         debugConfig         `json:"debug"`
 }
 
 // WindowConfig对应"window"字段
@@ -25,7 +26,8 @@ type windowConfig struct {
 
 // GraphicsConfig对应"graphics"字段
 type graphicsConfig struct {
-	Vsync bool `json:"vsync"`
+	Vsync        bool `json:"vsync"`
+	TextureAtlas bool `json:"texture_atlas"`
 }
 
 // PerformanceConfig对应"performance"字段
@@ -39,6 +41,11 @@ type audioConfig struct {
 	SoundVolume float32 `json:"sound_volume"`
 }
 
+// DebugConfig对应"debug"字段
+type debugConfig struct {
+	HotReload bool `json:"hot_reload"`
+}
+
 // 管理应用程序配置
 type Config struct {
 	// 窗口标题
@@ -51,6 +58,8 @@ type Config struct {
 	WindowResizable bool
 	// 是否开启垂直同步
 	VsyncEnabled bool
+	// 是否将小图片打包到纹理图集
+	TextureAtlasEnabled bool
 	// 目标帧率
 	TargetFPS int
 	// 音效大小
@@ -59,6 +68,8 @@ type Config struct {
 	MusicVolume float32
 	// 按键映射
 	InputMappings map[string][]string
+	// 是否开启资源热重载(监视assets目录)
+	HotReloadEnabled bool
 }
 
 // 创建配置
@@ -76,19 +87,21 @@ func (c *Config) Init() {
 	c.WindowHeight = 720
 	c.WindowResizable = true
 	c.VsyncEnabled = true
+	c.TextureAtlasEnabled = true
 	c.TargetFPS = 144
 	c.SoundVolume = 0.5
 	c.MusicVolume = 0.5
+	c.HotReloadEnabled = false
 	c.InputMappings = make(map[string][]string)
 
 	// 一些默认按键映射
-	c.InputMappings["move_up"] = []string{"W", "Up"}
-	c.InputMappings["move_down"] = []string{"S", "Down"}
-	c.InputMappings["move_left"] = []string{"A", "Left"}
-	c.InputMappings["move_right"] = []string{"D", "Right"}
-	c.InputMappings["jump"] = []string{"J", "Space"}
-	c.InputMappings["pause"] = []string{"P", "Escape"}
-	c.InputMappings["attack"] = []string{"K", "MouseLeft"}
+	c.InputMappings["move_up"] = []string{"W", "Up", "GamepadDpadUp"}
+	c.InputMappings["move_down"] = []string{"S", "Down", "GamepadDpadDown"}
+	c.InputMappings["move_left"] = []string{"A", "Left", "GamepadDpadLeft"}
+	c.InputMappings["move_right"] = []string{"D", "Right", "GamepadDpadRight"}
+	c.InputMappings["jump"] = []string{"J", "Space", "GamepadSouth"}
+	c.InputMappings["pause"] = []string{"P", "Escape", "GamepadStart"}
+	c.InputMappings["attack"] = []string{"K", "MouseLeft", "GamepadWest"}
 }
 
 // 从文件中加载配置
@@ -115,10 +128,12 @@ func (c *Config) LoadFromFile(filePath string) bool {
 	c.WindowHeight = config.Window.Height
 	c.WindowResizable = config.Window.Resizable
 	c.VsyncEnabled = config.Graphics.Vsync
+	c.TextureAtlasEnabled = config.Graphics.TextureAtlas
 	c.TargetFPS = config.Performance.TargetFPS
 	c.SoundVolume = config.Audio.SoundVolume
 	c.MusicVolume = config.Audio.MusicVolume
 	c.InputMappings = config.InputMappings
+	c.HotReloadEnabled = config.Debug.HotReload
 
 	slog.Info("load config file success", slog.String("filePath", filePath))
 	return true
@@ -134,7 +149,8 @@ func (c *Config) SaveToFile(filePath string) bool {
 			Resizable: c.WindowResizable,
 		},
 		Graphics: graphicsConfig{
-			Vsync: c.VsyncEnabled,
+			Vsync:        c.VsyncEnabled,
+			TextureAtlas: c.TextureAtlasEnabled,
 		},
 		Performance: performanceConfig{
 			TargetFPS: c.TargetFPS,
@@ -144,6 +160,9 @@ func (c *Config) SaveToFile(filePath string) bool {
 			SoundVolume: c.SoundVolume,
 		},
 		InputMappings: c.InputMappings,
+		Debug: debugConfig{
+			HotReload: c.HotReloadEnabled,
+		},
 	}
 	data, err := json.MarshalIndent(configJson, "", "  ")
 	if err != nil {
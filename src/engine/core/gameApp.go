@@ -10,6 +10,8 @@ import (
 	"sunny_land/src/engine/render"
 	"sunny_land/src/engine/resource"
 	"sunny_land/src/engine/scene"
+	"sunny_land/src/engine/vfs"
+	"sunny_land/src/game/component/ai"
 	escene "sunny_land/src/game/scene"
 
 	"github.com/SunshineZzzz/purego-sdl3/sdl"
@@ -48,8 +50,17 @@ type GameApp struct {
 	textRenderer *render.TextRenderer
 	// 游戏状态器
 	gameState *GameState
+	// 资源文件监视器，未开启热重载时为nil
+	fileWatcher *resource.FileWatcher
 }
 
+const (
+	// 热重载监视的目录
+	hotReloadDir = "assets"
+	// 热重载轮询间隔(秒)
+	hotReloadInterval = 0.5
+)
+
 // 创建游戏应用
 func NewGameApp() *GameApp {
 	return &GameApp{
@@ -69,6 +80,7 @@ func (g *GameApp) Destroy() {
 	// 清理资源管理器
 	if g.resourceManager != nil {
 		g.resourceManager.Clear()
+		g.resourceManager.GetVFS().Close()
 		g.resourceManager = nil
 	}
 
@@ -78,6 +90,18 @@ func (g *GameApp) Destroy() {
 		g.textRenderer = nil
 	}
 
+	// 关闭手柄
+	if g.inputManager != nil {
+		g.inputManager.Close()
+		g.inputManager = nil
+	}
+
+	// 清理渲染器
+	if g.renderer != nil {
+		g.renderer.Close()
+		g.renderer = nil
+	}
+
 	// 清理SDL资源
 	if g.sdlRenderer != nil {
 		sdl.DestroyRenderer(g.sdlRenderer)
@@ -98,10 +122,13 @@ func (g *GameApp) init() bool {
 		!g.initResourceManager() || !g.initAudioPlayer() ||
 		!g.initRenderer() || !g.initCamera() || !g.initInputManager() ||
 		!g.initTextRenderer() || !g.initPhysicsEngine() || !g.initGameState() ||
-		!g.initContext() || !g.initSceneManager() {
+		!g.initContext() || !g.initSceneManager() || !g.initFileWatcher() {
 		return false
 	}
 
+	// 注册游戏AI行为，关卡加载时根据名称创建
+	ai.RegisterBehaviors()
+
 	// 创建第一个场景
 	scene := escene.NewTitleScene(g.context, g.sceneManager, nil)
 	// 添加场景到场景管理器
@@ -122,7 +149,7 @@ func (g *GameApp) initConfig() bool {
 // 初始化SDL
 func (g *GameApp) initSDL() bool {
 	// 初始化 SDL
-	if !sdl.Init(sdl.InitVideo | sdl.InitAudio | sdl.InitEvents) {
+	if !sdl.Init(sdl.InitVideo | sdl.InitAudio | sdl.InitEvents | sdl.InitGamepad) {
 		slog.Error("sdl init error", slog.String("error", sdl.GetError()))
 		return false
 	}
@@ -182,6 +209,8 @@ func (g *GameApp) initTimer() bool {
 // 初始化资源管理器
 func (g *GameApp) initResourceManager() bool {
 	g.resourceManager = resource.NewResourceManager(g.sdlRenderer)
+	g.resourceManager.SetTextureAtlasEnabled(g.config.TextureAtlasEnabled)
+	g.resourceManager.GetVFS().MountAssets(vfs.AssetsArchive, vfs.ModsDir)
 	slog.Debug("resource manager init success")
 	return true
 }
@@ -250,6 +279,16 @@ func (g *GameApp) initGameState() bool {
 	return true
 }
 
+// 初始化文件监视器，配置中开启热重载时才创建
+func (g *GameApp) initFileWatcher() bool {
+	if !g.config.HotReloadEnabled {
+		return true
+	}
+	g.fileWatcher = resource.NewFileWatcher(hotReloadDir, hotReloadInterval)
+	slog.Debug("file watcher init success")
+	return true
+}
+
 // 运行
 func (g *GameApp) Run() {
 	slog.Debug("game app run")
@@ -284,10 +323,26 @@ func (g *GameApp) HandleEvents() {
 
 // 更新
 func (g *GameApp) update(dt float64) {
+	// 处理资源热重载
+	g.hotReload(dt)
 	// 更新场景
 	g.sceneManager.Update(dt)
 }
 
+// 检查文件变化，资源原地重新加载，地图变化交给当前场景处理
+func (g *GameApp) hotReload(dt float64) {
+	if g.fileWatcher == nil {
+		return
+	}
+	for _, path := range g.fileWatcher.Update(dt) {
+		slog.Info("file changed", slog.String("path", path))
+		if g.resourceManager.ReloadFile(path) {
+			g.audioPlayer.OnAudioReloaded(path)
+		}
+		g.sceneManager.NotifyFileChanged(path)
+	}
+}
+
 // 渲染
 func (g *GameApp) render() {
 	// 清除屏幕
@@ -41,6 +41,37 @@ type InputManager struct {
 	shouldQuit bool
 	// 鼠标位置(屏幕坐标)
 	mousePosition mgl32.Vec2
+	// 已连接的手柄，设备ID<->手柄
+	gamepads map[sdl.JoystickID]*sdl.Gamepad
+}
+
+// 手柄按钮在inputToActionsMap中的键 = gamepadInputBase + 按钮值，避免与键盘扫描码、鼠标按钮冲突
+const gamepadInputBase uint32 = 0x10000
+
+// 手柄按钮名称<->按钮值，按钮按位置命名(South在Xbox手柄上为A，在PlayStation手柄上为叉)
+var gamepadButtonNames = map[string]sdl.GamepadButton{
+	"GamepadSouth":         sdl.GamepadButtonSouth,
+	"GamepadEast":          sdl.GamepadButtonEast,
+	"GamepadWest":          sdl.GamepadButtonWest,
+	"GamepadNorth":         sdl.GamepadButtonNorth,
+	"GamepadBack":          sdl.GamepadButtonBack,
+	"GamepadStart":         sdl.GamepadButtonStart,
+	"GamepadLeftShoulder":  sdl.GamepadButtonLeftShoulder,
+	"GamepadRightShoulder": sdl.GamepadButtonRightShoulder,
+	"GamepadDpadUp":        sdl.GamepadButtonDpadUp,
+	"GamepadDpadDown":      sdl.GamepadButtonDpadDown,
+	"GamepadDpadLeft":      sdl.GamepadButtonDpadLeft,
+	"GamepadDpadRight":     sdl.GamepadButtonDpadRight,
+}
+
+// 配置中没有定义时使用的UI导航动作默认映射
+var defaultUIMappings = map[string][]string{
+	"ui_up":      {"Up", "W", "GamepadDpadUp"},
+	"ui_down":    {"Down", "S", "GamepadDpadDown"},
+	"ui_left":    {"Left", "A", "GamepadDpadLeft"},
+	"ui_right":   {"Right", "D", "GamepadDpadRight"},
+	"ui_confirm": {"Return", "Space", "GamepadSouth"},
+	"ui_cancel":  {"Escape", "Backspace", "GamepadEast"},
 }
 
 // 创建输入管理器
@@ -54,6 +85,7 @@ func NewInputManager(sdlRenderer *sdl.Renderer, inputMappings *map[string][]stri
 		inputToActionsMap:   make(map[uint32][]string),
 		actionStates:        make(map[string]ActionState),
 		shouldQuit:          false,
+		gamepads:            make(map[sdl.JoystickID]*sdl.Gamepad),
 	}
 	im.initializeMappings(inputMappings)
 	// 获取初始鼠标位置
@@ -80,6 +112,13 @@ func (im *InputManager) initializeMappings(inputMappings *map[string][]string) {
 		slog.Debug("MouseRightClick not found in input mappings, add default mapping")
 		(*im.actionsToKeynameMap)["MouseRightClick"] = []string{"MouseRight"}
 	}
+	// UI导航动作同样添加默认映射
+	for action, keyNames := range defaultUIMappings {
+		if _, exists := (*im.actionsToKeynameMap)[action]; !exists {
+			slog.Debug("ui action not found in input mappings, add default mapping", slog.String("action", action))
+			(*im.actionsToKeynameMap)[action] = keyNames
+		}
+	}
 
 	// 遍历动作<->按键名称映射，构建按键<->动作映射
 	for action, keyNames := range *im.actionsToKeynameMap {
@@ -87,6 +126,7 @@ func (im *InputManager) initializeMappings(inputMappings *map[string][]string) {
 		for _, keyName := range keyNames {
 			scancode := sdl.GetScancodeFromName(keyName)
 			mouseButton := im.mouseButtonFromName(keyName)
+			gamepadButton, isGamepadButton := gamepadButtonNames[keyName]
 			if scancode != sdl.ScancodeUnknown {
 				im.inputToActionsMap[uint32(scancode)] = append(im.inputToActionsMap[uint32(scancode)], action)
 				slog.Debug("map action to keyboard scancode", slog.String("action", action), slog.String("keyName", keyName),
@@ -95,6 +135,11 @@ func (im *InputManager) initializeMappings(inputMappings *map[string][]string) {
 				im.inputToActionsMap[uint32(mouseButton)] = append(im.inputToActionsMap[uint32(mouseButton)], action)
 				slog.Debug("map action to mouse button", slog.String("action", action), slog.String("keyName", keyName),
 					slog.Any("mouseButton", mouseButton))
+			} else if isGamepadButton {
+				key := gamepadInputBase + uint32(gamepadButton)
+				im.inputToActionsMap[key] = append(im.inputToActionsMap[key], action)
+				slog.Debug("map action to gamepad button", slog.String("action", action), slog.String("keyName", keyName),
+					slog.Any("gamepadButton", gamepadButton))
 			} else {
 				slog.Warn("unknown key name in input mappings, ignore", slog.String("keyName", keyName), slog.String("action", action))
 			}
@@ -168,6 +213,33 @@ func (im *InputManager) processEvent(event sdl.Event) {
 				im.updateActionState(action, isDown, false)
 			}
 		}
+	case sdl.EventGamepadButtonDown, sdl.EventGamepadButtonUp:
+		button := event.GButton().Button
+		isDown := event.GButton().Down
+
+		if actions, exists := im.inputToActionsMap[gamepadInputBase+uint32(button)]; exists {
+			// 更新action状态
+			for _, action := range actions {
+				// 手柄按钮事件没有重复触发
+				im.updateActionState(action, isDown, false)
+			}
+		}
+	case sdl.EventGamepadAdded:
+		// 启动时已连接的手柄同样会收到添加事件
+		which := event.GDevice().Which
+		if gamepad := sdl.OpenGamepad(which); gamepad != nil {
+			im.gamepads[which] = gamepad
+			slog.Debug("gamepad added", slog.Any("which", which))
+		} else {
+			slog.Error("open gamepad failed", slog.Any("which", which), slog.String("error", sdl.GetError()))
+		}
+	case sdl.EventGamepadRemoved:
+		which := event.GDevice().Which
+		if gamepad, exists := im.gamepads[which]; exists {
+			sdl.CloseGamepad(gamepad)
+			delete(im.gamepads, which)
+			slog.Debug("gamepad removed", slog.Any("which", which))
+		}
 	case sdl.EventMouseMotion:
 		// 更新鼠标位置
 		im.mousePosition[0] = event.Motion().X
@@ -245,3 +317,11 @@ func (im *InputManager) GetLogicalMousePosition() mgl32.Vec2 {
 	sdl.RenderCoordinatesFromWindow(im.sdlRenderer, im.mousePosition[0], im.mousePosition[1], &logicalPos[0], &logicalPos[1])
 	return logicalPos
 }
+
+// 关闭所有已连接的手柄
+func (im *InputManager) Close() {
+	for which, gamepad := range im.gamepads {
+		sdl.CloseGamepad(gamepad)
+		delete(im.gamepads, which)
+	}
+}
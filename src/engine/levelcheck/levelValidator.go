@@ -0,0 +1,691 @@
+package levelcheck
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"sunny_land/src/engine/vfs"
+
+	"github.com/bitly/go-simplejson"
+)
+
+/**
+ * @brief 关卡校验器，不依赖SDL，只读取地图json与资源文件是否存在，可在无窗口环境下运行。
+ *
+ * 文件通过虚拟文件系统读取，挂载与游戏相同的资源包与模组后，校验的就是游戏实际读取的文件。
+ *
+ * 检查内容：
+ * 1. 图像图层、图块集、图块图片等纹理文件是否存在
+ * 2. "sound"属性中的音效文件、"music"地图属性中的音乐文件是否存在
+ * 3. 瓦片类型属性(solid/unisolid/hazard/ladder/slope)是否合法，图块是否有未知属性
+ * 4. "animation"/"sound"/"ai"/"particles"等json字符串属性是否能解析，animation为Aseprite精灵表路径时校验精灵表，
+ *    particles为粒子发射器json路径时校验该文件，ai的behavior是否为已知的行为(设置了SetAIBehaviors时)
+ * 5. "light"光源半径是否为正数，"light_texture"光源纹理是否存在，"light_color"/"ambient_light"颜色是否合法
+ * 6. 玩法关卡是否包含"player"对象与"main"图层
+ * 7. next_level触发器与"next_level"地图属性指向的地图是否存在
+ * 不包含对象图层的地图视为背景地图(例如标题界面)，不检查第6条。
+ */
+
+// 问题严重程度
+type Severity int
+
+const (
+	// 警告，不影响退出码
+	SeverityWarning Severity = iota
+	// 错误
+	SeverityError
+)
+
+func (s Severity) String() string {
+	if s == SeverityError {
+		return "ERROR"
+	}
+	return "WARN"
+}
+
+// 校验发现的问题
+type Issue struct {
+	// 问题所在文件
+	FilePath string
+	// 严重程度
+	Severity Severity
+	// 问题描述
+	Message string
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s %s: %s", i.Severity, i.FilePath, i.Message)
+}
+
+// 地图中引用的图块集
+type tilesetRef struct {
+	// 第一个全局Id
+	firstGId int
+	// 图块集路径
+	path string
+	// 图块集数据，解析失败为nil
+	data *simplejson.Json
+}
+
+// 关卡校验器
+type LevelValidator struct {
+	// 发现的问题
+	issues []Issue
+	// 已解析的图块集，路径 -> 数据(解析失败为nil)，多个地图共享的图块集只检查一次
+	tilesets map[string]*simplejson.Json
+	// 已知的AI行为名称，nil表示不检查行为名称
+	aiBehaviors map[string]bool
+}
+
+// 创建关卡校验器
+func NewLevelValidator() *LevelValidator {
+	return &LevelValidator{
+		issues:   make([]Issue, 0),
+		tilesets: make(map[string]*simplejson.Json),
+	}
+}
+
+// 设置已知的AI行为名称，"ai"属性引用其他行为时报告错误
+func (lv *LevelValidator) SetAIBehaviors(names []string) {
+	lv.aiBehaviors = make(map[string]bool, len(names))
+	for _, name := range names {
+		lv.aiBehaviors[name] = true
+	}
+}
+
+// 获取所有问题
+func (lv *LevelValidator) GetIssues() []Issue {
+	return lv.issues
+}
+
+// 获取错误数量
+func (lv *LevelValidator) ErrorCount() int {
+	count := 0
+	for _, issue := range lv.issues {
+		if issue.Severity == SeverityError {
+			count++
+		}
+	}
+	return count
+}
+
+// 记录错误
+func (lv *LevelValidator) errorf(filePath, format string, args ...any) {
+	lv.issues = append(lv.issues, Issue{FilePath: filePath, Severity: SeverityError, Message: fmt.Sprintf(format, args...)})
+}
+
+// 记录警告
+func (lv *LevelValidator) warnf(filePath, format string, args ...any) {
+	lv.issues = append(lv.issues, Issue{FilePath: filePath, Severity: SeverityWarning, Message: fmt.Sprintf(format, args...)})
+}
+
+/**
+ * @brief 校验目录下所有.tmj地图
+ * @param dir 地图目录，例如"assets/maps"
+ * @return int 校验的地图数量
+ */
+func (lv *LevelValidator) ValidateDir(dir string) int {
+	// 包括只存在于资源包或模组中的地图，结果已排序
+	mapPaths, err := vfs.GetVFS().Glob(filepath.Join(dir, "*.tmj"))
+	if err != nil {
+		lv.errorf(dir, "glob map files failed: %v", err)
+		return 0
+	}
+	if len(mapPaths) == 0 {
+		lv.warnf(dir, "no .tmj map found")
+		return 0
+	}
+	for _, mapPath := range mapPaths {
+		lv.ValidateMap(mapPath)
+	}
+	return len(mapPaths)
+}
+
+/**
+ * @brief 校验单个.tmj地图
+ * @param mapPath 地图路径
+ */
+func (lv *LevelValidator) ValidateMap(mapPath string) {
+	root := lv.readJson(mapPath)
+	if root == nil {
+		return
+	}
+
+	// 图块集
+	tilesets := make([]tilesetRef, 0)
+	tilesetsJson := root.Get("tilesets")
+	for i := 0; i < len(tilesetsJson.MustArray()); i++ {
+		tileset := tilesetsJson.GetIndex(i)
+		source := tileset.Get("source").MustString("")
+		if source == "" {
+			lv.errorf(mapPath, "tileset %d lacks source (embedded tilesets are not supported)", i)
+			continue
+		}
+		tilesetPath := resolvePath(source, mapPath)
+		tilesets = append(tilesets, tilesetRef{
+			firstGId: tileset.Get("firstgid").MustInt(0),
+			path:     tilesetPath,
+			data:     lv.validateTileset(tilesetPath),
+		})
+	}
+	sort.Slice(tilesets, func(i, j int) bool { return tilesets[i].firstGId < tilesets[j].firstGId })
+
+	ctx := &mapContext{
+		mapPath:  mapPath,
+		tilesets: tilesets,
+	}
+	if layers, ok := root.CheckGet("layers"); ok {
+		lv.validateLayers(ctx, layers)
+	} else {
+		lv.errorf(mapPath, "map has no layers")
+	}
+
+	// 地图属性
+	props := propertiesToMap(root)
+	if music, ok := props["music"].(string); ok && music != "" {
+		musicPath := music
+		if propertyType(root, "music") == "file" {
+			musicPath = resolvePath(music, mapPath)
+		}
+		lv.checkFile(mapPath, musicPath, "music")
+	}
+	if ambient, ok := props["ambient_light"].(string); ok && ambient != "" && !isTiledColor(ambient) {
+		lv.errorf(mapPath, "malformed ambient_light color %q", ambient)
+	}
+	if bounds, ok := props["camera_bounds"].(string); ok && bounds != "" {
+		if _, err := simplejson.NewJson([]byte(bounds)); err != nil {
+			lv.errorf(mapPath, "malformed camera_bounds json: %v", err)
+		}
+	}
+	if nextLevel, ok := props["next_level"].(string); ok && nextLevel != "" {
+		ctx.nextLevels = append(ctx.nextLevels, nextLevel)
+	} else if ctx.hasUnnamedTrigger {
+		lv.errorf(mapPath, "unnamed next_level trigger without \"next_level\" map property")
+	}
+
+	// 玩法关卡必须有玩家与主图层
+	if ctx.hasObjectLayer {
+		if !ctx.hasPlayer {
+			lv.errorf(mapPath, "\"player\" object not found")
+		}
+		if !ctx.hasMainLayer {
+			lv.errorf(mapPath, "\"main\" tile layer not found")
+		}
+	}
+
+	// 下一关卡
+	for _, nextLevel := range ctx.nextLevels {
+		nextPath := levelNameToPath(nextLevel, mapPath)
+		if !vfs.GetVFS().Exists(nextPath) {
+			lv.errorf(mapPath, "next level %q points at missing map %s", nextLevel, nextPath)
+		}
+	}
+}
+
+// 单个地图校验过程中的状态
+type mapContext struct {
+	// 地图路径
+	mapPath string
+	// 图块集，按firstGId升序
+	tilesets []tilesetRef
+	// 是否包含对象图层
+	hasObjectLayer bool
+	// 是否找到玩家对象
+	hasPlayer bool
+	// 是否找到main图块图层
+	hasMainLayer bool
+	// 是否存在未命名的next_level触发器
+	hasUnnamedTrigger bool
+	// 下一关卡名称
+	nextLevels []string
+}
+
+// 递归校验图层数组
+func (lv *LevelValidator) validateLayers(ctx *mapContext, layers *simplejson.Json) {
+	for i := 0; i < len(layers.MustArray()); i++ {
+		layer := layers.GetIndex(i)
+		layerName := layer.Get("name").MustString("Unnamed")
+		switch layerType := layer.Get("type").MustString("none"); layerType {
+		case "group":
+			if children, ok := layer.CheckGet("layers"); ok {
+				lv.validateLayers(ctx, children)
+			}
+		case "imagelayer":
+			image := layer.Get("image").MustString("")
+			if image == "" {
+				lv.errorf(ctx.mapPath, "image layer %q lacks texture path", layerName)
+				continue
+			}
+			lv.checkFile(ctx.mapPath, resolvePath(image, ctx.mapPath), fmt.Sprintf("texture of image layer %q", layerName))
+		case "tilelayer":
+			if layerName == "main" {
+				ctx.hasMainLayer = true
+			}
+			data := layer.Get("data")
+			if data.MustArray() == nil {
+				lv.errorf(ctx.mapPath, "tile layer %q lacks data (only csv encoding is supported)", layerName)
+				continue
+			}
+			// 同一个gid只报告一次
+			reported := make(map[int]bool)
+			for j := 0; j < len(data.MustArray()); j++ {
+				gId := data.GetIndex(j).MustInt(0)
+				if gId == 0 || reported[gId] {
+					continue
+				}
+				if msg := ctx.checkGId(gId); msg != "" {
+					lv.errorf(ctx.mapPath, "tile layer %q: %s", layerName, msg)
+					reported[gId] = true
+				}
+			}
+		case "objectgroup":
+			if !layer.Get("visible").MustBool(true) {
+				continue
+			}
+			ctx.hasObjectLayer = true
+			lv.validateObjects(ctx, layer)
+		default:
+			lv.warnf(ctx.mapPath, "unknown layer type %q of layer %q", layerType, layerName)
+		}
+	}
+}
+
+// 校验对象图层中的对象
+func (lv *LevelValidator) validateObjects(ctx *mapContext, layer *simplejson.Json) {
+	layerName := layer.Get("name").MustString("Unnamed")
+	objects := layer.Get("objects")
+	for i := 0; i < len(objects.MustArray()); i++ {
+		obj := objects.GetIndex(i)
+		name := obj.Get("name").MustString("Unnamed")
+		gId := obj.Get("gid").MustInt(0)
+		var tileJson *simplejson.Json
+		if gId != 0 {
+			if msg := ctx.checkGId(gId); msg != "" {
+				lv.errorf(ctx.mapPath, "object %q in layer %q: %s", name, layerName, msg)
+				continue
+			}
+			tileJson = ctx.tileJson(gId)
+		}
+
+		if name == "player" {
+			ctx.hasPlayer = true
+		}
+
+		// 对象自身的属性优先于图块集中的属性
+		props := propertiesToMap(tileJson)
+		for key, value := range propertiesToMap(obj) {
+			props[key] = value
+		}
+		if tag, _ := props["tag"].(string); tag == "next_level" {
+			if name == "" || name == "Unnamed" {
+				// 未命名的触发器使用地图属性中的next_level
+				ctx.hasUnnamedTrigger = true
+			} else {
+				ctx.nextLevels = append(ctx.nextLevels, name)
+			}
+		}
+		// 对象自身定义的json属性需要单独检查，图块集中的在validateTileset中已检查
+		lv.validateJsonProperties(ctx.mapPath, fmt.Sprintf("object %q", name), propertiesToMap(obj))
+	}
+}
+
+/**
+ * @brief 读取并校验图块集，结果缓存
+ * @param tilesetPath 图块集路径
+ * @return *simplejson.Json 图块集数据，失败返回nil
+ */
+func (lv *LevelValidator) validateTileset(tilesetPath string) *simplejson.Json {
+	if tileset, ok := lv.tilesets[tilesetPath]; ok {
+		return tileset
+	}
+	tileset := lv.readJson(tilesetPath)
+	lv.tilesets[tilesetPath] = tileset
+	if tileset == nil {
+		return nil
+	}
+
+	// 整张图块集
+	if image, ok := tileset.CheckGet("image"); ok {
+		lv.checkFile(tilesetPath, resolvePath(image.MustString(""), tilesetPath), "tileset image")
+		if tileset.Get("columns").MustInt(0) <= 0 {
+			lv.errorf(tilesetPath, "tileset image has no columns")
+		}
+	}
+
+	tiles := tileset.Get("tiles")
+	for i := 0; i < len(tiles.MustArray()); i++ {
+		tile := tiles.GetIndex(i)
+		id := tile.Get("id").MustInt(0)
+		owner := fmt.Sprintf("tile %d", id)
+		// 多张图片组成的图块集
+		if _, ok := tileset.CheckGet("image"); !ok {
+			image := tile.Get("image").MustString("")
+			if image == "" {
+				lv.errorf(tilesetPath, "%s has no image", owner)
+			} else {
+				lv.checkFile(tilesetPath, resolvePath(image, tilesetPath), owner+" image")
+			}
+		}
+		lv.validateTileType(tilesetPath, owner, tile)
+		lv.validateJsonProperties(tilesetPath, owner, propertiesToMap(tile))
+	}
+	return tileset
+}
+
+// 合法的斜坡类型
+var slopeTypes = map[string]bool{"0_1": true, "1_0": true, "0_2": true, "2_1": true, "1_2": true, "2_0": true}
+
+// 关卡加载器读取的图块属性，其他属性不会生效，通常是拼写错误(例如"soild")
+var knownTileProperties = map[string]bool{
+	"solid": true, "unisolid": true, "hazard": true, "ladder": true, "slope": true,
+	"tag": true, "gravity": true, "health": true, "draw_layer": true,
+	"animation": true, "sound": true, "ai": true, "particles": true,
+	"light": true, "light_color": true, "light_texture": true, "light_flicker": true,
+}
+
+// 校验瓦片类型属性，并报告未知的属性
+func (lv *LevelValidator) validateTileType(filePath, owner string, tile *simplejson.Json) {
+	properties := tile.Get("properties")
+	for i := 0; i < len(properties.MustArray()); i++ {
+		prop := properties.GetIndex(i)
+		name := prop.Get("name").MustString("")
+		if !knownTileProperties[name] {
+			lv.errorf(filePath, "%s: unknown tile property %q", owner, name)
+			continue
+		}
+		switch name {
+		case "solid", "unisolid", "hazard", "ladder":
+			if _, err := prop.Get("value").Bool(); err != nil {
+				lv.errorf(filePath, "%s: tile type property %q must be bool", owner, name)
+			}
+		case "slope":
+			if slope := prop.Get("value").MustString(""); !slopeTypes[slope] {
+				lv.errorf(filePath, "%s: unknown slope type %q", owner, slope)
+			}
+		}
+	}
+}
+
+// 校验json字符串属性(animation/sound/ai/particles)
+func (lv *LevelValidator) validateJsonProperties(filePath, owner string, props map[string]any) {
+	if value, ok := props["animation"]; ok {
+		lv.validateAnimation(filePath, owner, value)
+	}
+	if value, ok := props["sound"]; ok {
+		lv.validateSound(filePath, owner, value)
+	}
+	if value, ok := props["ai"]; ok {
+		lv.validateAI(filePath, owner, value)
+	}
+	if value, ok := props["particles"]; ok {
+		lv.validateParticles(filePath, owner, value)
+	}
+	if value, ok := props["light"]; ok {
+		lv.validateLight(filePath, owner, value, props)
+	}
+}
+
+// 校验光源属性：半径为正数，颜色合法，纹理存在
+func (lv *LevelValidator) validateLight(filePath, owner string, value any, props map[string]any) {
+	radius, err := strconv.ParseFloat(fmt.Sprint(value), 64)
+	if err != nil || radius <= 0.0 {
+		lv.errorf(filePath, "%s light radius %v must be a positive number", owner, value)
+	}
+	if color, ok := props["light_color"].(string); ok && color != "" && !isTiledColor(color) {
+		lv.errorf(filePath, "%s has malformed light_color %q", owner, color)
+	}
+	if texture, ok := props["light_texture"].(string); ok && strings.TrimSpace(texture) != "" {
+		lv.checkFile(filePath, strings.TrimSpace(texture), "light texture")
+	}
+}
+
+// 是否为合法的Tiled颜色字符串，"#RRGGBB"或"#AARRGGBB"
+func isTiledColor(color string) bool {
+	hex := strings.TrimPrefix(color, "#")
+	if len(hex) != 6 && len(hex) != 8 {
+		return false
+	}
+	_, err := strconv.ParseUint(hex, 16, 32)
+	return err == nil
+}
+
+// 解析json字符串属性，失败记录错误并返回nil
+func (lv *LevelValidator) parseJsonProperty(filePath, owner, name string, value any) *simplejson.Json {
+	str, ok := value.(string)
+	if !ok {
+		lv.errorf(filePath, "%s: %q property must be a json string", owner, name)
+		return nil
+	}
+	result, err := simplejson.NewJson([]byte(str))
+	if err != nil {
+		lv.errorf(filePath, "%s: malformed %q json: %v", owner, name, err)
+		return nil
+	}
+	if _, err := result.Map(); err != nil {
+		lv.errorf(filePath, "%s: %q json must be an object", owner, name)
+		return nil
+	}
+	return result
+}
+
+// 校验动画属性，{"动画名": {"duration":100, "row":0, "frames":[0,1,2]}}，或Aseprite导出的json精灵表路径
+func (lv *LevelValidator) validateAnimation(filePath, owner string, value any) {
+	if str, ok := value.(string); ok && strings.HasSuffix(strings.ToLower(strings.TrimSpace(str)), ".json") {
+		lv.validateAseprite(filePath, owner, strings.TrimSpace(str))
+		return
+	}
+	animJson := lv.parseJsonProperty(filePath, owner, "animation", value)
+	if animJson == nil {
+		return
+	}
+	for animName := range animJson.MustMap() {
+		frames, ok := animJson.Get(animName).CheckGet("frames")
+		if !ok || len(frames.MustArray()) == 0 {
+			lv.errorf(filePath, "%s: animation %q has no frames", owner, animName)
+		}
+	}
+}
+
+// 校验Aseprite精灵表，帧与图片必须存在，帧标签范围不能越界
+func (lv *LevelValidator) validateAseprite(filePath, owner, sheetPath string) {
+	sheet := lv.readJson(sheetPath)
+	if sheet == nil {
+		lv.errorf(filePath, "%s: invalid aseprite sheet %s", owner, sheetPath)
+		return
+	}
+	frameCount := len(sheet.Get("frames").MustArray())
+	if frameCount == 0 {
+		frameCount = len(sheet.Get("frames").MustMap())
+	}
+	if frameCount == 0 {
+		lv.errorf(sheetPath, "aseprite sheet has no frames")
+		return
+	}
+	if image := sheet.GetPath("meta", "image").MustString(""); image != "" {
+		lv.checkFile(sheetPath, filepath.Join(filepath.Dir(sheetPath), image), "aseprite image")
+	}
+	for i := range sheet.GetPath("meta", "frameTags").MustArray() {
+		tag := sheet.GetPath("meta", "frameTags").GetIndex(i)
+		from, to := tag.Get("from").MustInt(-1), tag.Get("to").MustInt(-1)
+		if from < 0 || to >= frameCount || from > to {
+			lv.errorf(sheetPath, "frame tag %q range [%d, %d] out of %d frames", tag.Get("name").MustString(""), from, to, frameCount)
+		}
+	}
+}
+
+// 校验粒子发射器属性，json字符串或粒子发射器json文件路径，纹理必须存在且至少发射一个粒子
+func (lv *LevelValidator) validateParticles(filePath, owner string, value any) {
+	var config *simplejson.Json
+	if str, ok := value.(string); ok && strings.HasSuffix(strings.ToLower(strings.TrimSpace(str)), ".json") {
+		filePath = strings.TrimSpace(str)
+		if config = lv.readJson(filePath); config == nil {
+			return
+		}
+		owner = "particle emitter"
+	} else if config = lv.parseJsonProperty(filePath, owner, "particles", value); config == nil {
+		return
+	}
+	texture := config.Get("texture").MustString("")
+	if texture == "" {
+		lv.errorf(filePath, "%s has no texture", owner)
+	} else {
+		lv.checkFile(filePath, texture, "particle texture")
+	}
+	if config.Get("rate").MustFloat64(0.0) <= 0.0 && config.Get("burst").MustInt(0) <= 0 {
+		lv.errorf(filePath, "%s emits nothing, set rate or burst", owner)
+	}
+}
+
+// 校验音效属性，{"音效名": "音效路径"}，路径相对于可执行文件
+func (lv *LevelValidator) validateSound(filePath, owner string, value any) {
+	soundJson := lv.parseJsonProperty(filePath, owner, "sound", value)
+	if soundJson == nil {
+		return
+	}
+	for soundName := range soundJson.MustMap() {
+		soundPath := soundJson.Get(soundName).MustString("")
+		if soundPath == "" {
+			lv.errorf(filePath, "%s: sound %q has empty path", owner, soundName)
+			continue
+		}
+		lv.checkFile(filePath, soundPath, fmt.Sprintf("%s sound %q", owner, soundName))
+	}
+}
+
+// 校验AI属性，{"behavior":"patrol", ...}
+func (lv *LevelValidator) validateAI(filePath, owner string, value any) {
+	aiJson := lv.parseJsonProperty(filePath, owner, "ai", value)
+	if aiJson == nil {
+		return
+	}
+	behavior := aiJson.Get("behavior").MustString("")
+	if behavior == "" {
+		lv.errorf(filePath, "%s: ai json has no behavior", owner)
+		return
+	}
+	if lv.aiBehaviors != nil && !lv.aiBehaviors[behavior] {
+		lv.errorf(filePath, "%s: unknown ai behavior %q", owner, behavior)
+	}
+}
+
+// 检查文件是否存在(虚拟文件系统中)
+func (lv *LevelValidator) checkFile(filePath, target, what string) {
+	if !vfs.GetVFS().Exists(target) {
+		lv.errorf(filePath, "missing %s: %s", what, target)
+	}
+}
+
+// 通过虚拟文件系统读取并解析json文件，失败记录错误并返回nil
+func (lv *LevelValidator) readJson(filePath string) *simplejson.Json {
+	data, err := vfs.GetVFS().ReadFile(filePath)
+	if err != nil {
+		lv.errorf(filePath, "read file failed: %v", err)
+		return nil
+	}
+	root, err := simplejson.NewJson(data)
+	if err != nil {
+		lv.errorf(filePath, "parse json failed: %v", err)
+		return nil
+	}
+	return root
+}
+
+/**
+ * @brief 检查全局Id是否能找到对应的图块
+ * @param gId 全局Id
+ * @return string 问题描述，没有问题返回空字符串
+ */
+func (ctx *mapContext) checkGId(gId int) string {
+	ref := ctx.findTileset(gId)
+	if ref == nil {
+		return fmt.Sprintf("no tileset for gid %d", gId)
+	}
+	if ref.data == nil {
+		// 图块集本身的问题已经报告过
+		return ""
+	}
+	localId := gId - ref.firstGId
+	if _, ok := ref.data.CheckGet("image"); ok {
+		if count := ref.data.Get("tilecount").MustInt(0); count > 0 && localId >= count {
+			return fmt.Sprintf("gid %d out of tileset %s", gId, ref.path)
+		}
+		return ""
+	}
+	if ctx.tileJson(gId) == nil {
+		return fmt.Sprintf("gid %d not found in tileset %s", gId, ref.path)
+	}
+	return ""
+}
+
+// 查找全局Id所属的图块集，即firstGId小于等于gId的最后一个
+func (ctx *mapContext) findTileset(gId int) *tilesetRef {
+	var found *tilesetRef
+	for i := range ctx.tilesets {
+		if ctx.tilesets[i].firstGId <= gId {
+			found = &ctx.tilesets[i]
+		}
+	}
+	return found
+}
+
+// 获取全局Id对应的图块json，不存在返回nil
+func (ctx *mapContext) tileJson(gId int) *simplejson.Json {
+	ref := ctx.findTileset(gId)
+	if ref == nil || ref.data == nil {
+		return nil
+	}
+	localId := gId - ref.firstGId
+	tiles := ref.data.Get("tiles")
+	for i := 0; i < len(tiles.MustArray()); i++ {
+		tile := tiles.GetIndex(i)
+		if tile.Get("id").MustInt(0) == localId {
+			return tile
+		}
+	}
+	return nil
+}
+
+// 将Tiled属性数组转换为 属性名 -> 属性值 映射
+func propertiesToMap(owner *simplejson.Json) map[string]any {
+	result := make(map[string]any)
+	if owner == nil {
+		return result
+	}
+	properties := owner.Get("properties")
+	for i := 0; i < len(properties.MustArray()); i++ {
+		prop := properties.GetIndex(i)
+		if name := prop.Get("name").MustString(""); name != "" {
+			result[name] = prop.Get("value").Interface()
+		}
+	}
+	return result
+}
+
+// 获取Tiled属性数组中指定属性的类型
+func propertyType(owner *simplejson.Json, propName string) string {
+	properties := owner.Get("properties")
+	for i := 0; i < len(properties.MustArray()); i++ {
+		prop := properties.GetIndex(i)
+		if prop.Get("name").MustString("") == propName {
+			return prop.Get("type").MustString("string")
+		}
+	}
+	return ""
+}
+
+// 解析相对于文件的路径，与关卡加载器的规则一致
+func resolvePath(relativePath, filePath string) string {
+	return filepath.Join(filepath.Dir(filePath), relativePath)
+}
+
+// 关卡名称转换为地图路径，与游戏场景的规则一致："level2" -> "<地图目录>/level2.tmj"
+func levelNameToPath(levelName, mapPath string) string {
+	projectName, _, _ := strings.Cut(levelName, "#")
+	if filepath.Ext(projectName) == "" {
+		projectName += ".tmj"
+	}
+	return filepath.Join(filepath.Dir(mapPath), projectName)
+}
@@ -20,8 +20,30 @@ type IRenderer interface {
 	DrawSprite(ICamera, ISprite, mgl32.Vec2, mgl32.Vec2, float64)
 	// 绘制填充矩形
 	DrawUIFilledRect(emath.Rect, emath.FColor)
+	// 绘制矩形边框，参数为矩形区域、边框宽度、颜色
+	DrawUIRect(emath.Rect, float32, emath.FColor)
 	// 绘制用户界面精灵图
 	DrawUISprite(ISprite, mgl32.Vec2, *mgl32.Vec2)
+	// 九宫格绘制用户界面精灵图，参数为目标矩形、源图中的边框宽度、边框缩放
+	DrawUINineSlice(ISprite, emath.Rect, emath.Insets, float32)
+	// 设置颜色调制(RGB为色调，A为不透明度)，作用于之后所有的精灵图绘制
+	SetColorMod(emath.FColor)
+	// 重置颜色调制为白色不透明
+	ResetColorMod()
+	// 设置之后绘制的精灵图的图层与排序键，越大越靠前
+	SetDrawOrder(int, float32)
+	// 重置图层与排序键为默认值
+	ResetDrawOrder()
+	// 绘制整张预乘alpha的纹理(例如渲染目标)到世界坐标(左上角)，size为世界中的尺寸
+	DrawTexture(ICamera, *sdl.Texture, mgl32.Vec2, mgl32.Vec2)
+	// 创建渲染目标纹理
+	CreateRenderTarget(int32, int32) *sdl.Texture
+	// 将绘制重定向到渲染目标纹理，回调中的绘制立即执行，坐标为纹理像素坐标
+	RenderToTexture(*sdl.Texture, func()) bool
+	// 获取纹理重新加载的次数
+	GetTextureGeneration() uint64
+	// 绘制光源精灵图，参数为光源中心的世界坐标、世界中的尺寸、颜色(A为强度)
+	DrawLight(ICamera, ISprite, mgl32.Vec2, mgl32.Vec2, emath.FColor)
 }
 
 // 摄像机抽象
@@ -36,6 +58,14 @@ type ICamera interface {
 	WorldToScreen(mgl32.Vec2) mgl32.Vec2
 	// 移动相机
 	Move(mgl32.Vec2)
+	// 获取相机位置
+	GetPosition() mgl32.Vec2
+	// 屏幕坐标(视口坐标)转换为世界坐标
+	ScreenToWorld(mgl32.Vec2) mgl32.Vec2
+	// 获取视口在世界中的尺寸，考虑缩放
+	GetViewSize() mgl32.Vec2
+	// 获取缩放倍数
+	GetZoom() float32
 }
 
 // 精灵图抽象
@@ -46,6 +76,10 @@ type ISprite interface {
 	GetTextureId() string
 	// 获取是否水平反转
 	GetIsFlipped() bool
+	// 获取颜色调制(RGB为色调，A为不透明度)
+	GetColor() emath.FColor
+	// 获取混合模式
+	GetBlendMode() sdl.BlendMode
 }
 
 // 代表动画中的单个帧
@@ -54,6 +88,10 @@ type AnimationFrame struct {
 	SourceRect *sdl.FRect
 	// 此帧的显示时间(秒)
 	Duration float64
+	// 裁剪掉透明边缘的帧在原始帧中的偏移，未裁剪为0
+	Offset mgl32.Vec2
+	// 原始帧尺寸，未裁剪为0(即与SourceRect尺寸相同)
+	SourceSize mgl32.Vec2
 }
 
 // 动画抽象
@@ -244,6 +282,8 @@ type ITileLayerComponent interface {
 	GetTileSize() mgl32.Vec2
 	// 获取指定位置的瓦片类型，pos不是整数坐标
 	GetTileTypeAt(int, int) TileType
+	// 获取瓦片层在世界中的偏移量，世界坐标减去偏移量后再换算为瓦片坐标
+	GetOffset() mgl32.Vec2
 	// 设置物理引擎
 	SetPhysicsEngine(*PhysicsEngine)
 }
@@ -262,6 +302,9 @@ type TileTriggerEventPair struct {
 	TileType TileType
 }
 
+// 默认重力加速度，单位：像素每二次方秒
+var DefaultGravity = mgl32.Vec2{0.0, 980.0}
+
 // 物理引擎，负责管理和模拟物理行为，碰撞检测
 type PhysicsEngine struct {
 	// 注册的物理组件容器
@@ -285,7 +328,7 @@ func NewPhysicsEngine() *PhysicsEngine {
 	slog.Debug("new physics engine")
 	return &PhysicsEngine{
 		physicsComponents: make([]IPhysicsComponent, 0),
-		gravity:           mgl32.Vec2{0.0, 980.0},
+		gravity:           DefaultGravity,
 		maxSpeed:          500.0,
 		collisionPairs:    make([]CollisionPair, 0),
 		tileTriggerEvents: make([]TileTriggerEventPair, 0),
@@ -472,6 +515,9 @@ func (pe *PhysicsEngine) resolveTileLayerCollisions(pc IPhysicsComponent, deltaT
 
 		// 获取瓦片大小
 		tileSize := tl.GetTileSize()
+		// 在瓦片层的局部坐标中检测(减去图层偏移量)，最终位移是两个位置的差，与偏移量无关
+		objPos := objPos.Sub(tl.GetOffset())
+		newObjPos := newObjPos.Sub(tl.GetOffset())
 		// 采用轴分离碰撞检测，如果不这样做就会出现问题，比如：
 		// 我想往右走1像素，同时往上走1像素。计算目标位置：现在的坐标(x, y)变成(x+1, y+1)。刚好(x+1, y+1)有一堵墙(碰撞)。这次移动是非法的，程序把你的坐标锁定在原地(x, y)
 		// 玩家的感受：我按着“右”和“上”，角色动都不动，像被胶水粘在了墙上。所以需要分离。处理X轴(向右走1像素)，发现(x+1, y)确实撞墙了。取消这次X轴位移，保持x不变。
@@ -748,6 +794,16 @@ func (pe *PhysicsEngine) getTileHeightAtWidth(width float32, tileType TileType,
 	}
 }
 
+// 设置重力加速度
+func (pe *PhysicsEngine) SetGravity(gravity mgl32.Vec2) {
+	pe.gravity = gravity
+}
+
+// 获取重力加速度
+func (pe *PhysicsEngine) GetGravity() mgl32.Vec2 {
+	return pe.gravity
+}
+
 // 设置世界边界
 func (pe *PhysicsEngine) SetWorldBounds(bounds *emath.Rect) {
 	pe.worldBounds = bounds
@@ -824,11 +880,13 @@ func (pe *PhysicsEngine) checkTileTriggers() {
 			tileSize := tileLayerComp.GetTileSize()
 			// 检查右边缘和下边缘时，需要减1像素，否则会检查到下一行/列的瓦片
 			tolernance := float32(1.0)
+			// 瓦片层局部坐标中的位置(减去图层偏移量)
+			position := worldAABB.Position.Sub(tileLayerComp.GetOffset())
 			// 获取瓦片坐标范围
-			startX := int(math.Floor(float64(worldAABB.Position.X() / tileSize.X())))
-			endX := int(math.Ceil(float64((worldAABB.Position.X() + worldAABB.Size.X() - tolernance) / tileSize.X())))
-			startY := int(math.Floor(float64(worldAABB.Position.Y() / tileSize.Y())))
-			endY := int(math.Ceil(float64((worldAABB.Position.Y() + worldAABB.Size.Y() - tolernance) / tileSize.Y())))
+			startX := int(math.Floor(float64(position.X() / tileSize.X())))
+			endX := int(math.Ceil(float64((position.X() + worldAABB.Size.X() - tolernance) / tileSize.X())))
+			startY := int(math.Floor(float64(position.Y() / tileSize.Y())))
+			endY := int(math.Ceil(float64((position.Y() + worldAABB.Size.Y() - tolernance) / tileSize.Y())))
 
 			// 遍历瓦片坐标范围，检查是否有触发器类型的瓦片
 			for x := startX; x < endX; x++ {
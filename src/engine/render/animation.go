@@ -43,6 +43,12 @@ func (a *Animation) AddFrame(rect *sdl.FRect, duration float64) {
 	a.totalDuration += duration
 }
 
+// 向动画添加已创建的帧，帧可以被多个动画共享
+func (a *Animation) addAnimationFrame(frame *physics.AnimationFrame) {
+	a.frames = append(a.frames, frame)
+	a.totalDuration += frame.Duration
+}
+
 // 获取在给定时间点应该显示的动画帧
 func (a *Animation) GetFrameAtTime(time float64) *physics.AnimationFrame {
 	if len(a.frames) == 0 {
@@ -0,0 +1,227 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"path"
+	"strconv"
+
+	"sunny_land/src/engine/physics"
+	"sunny_land/src/engine/resource"
+
+	"github.com/SunshineZzzz/purego-sdl3/sdl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Aseprite导出json中的矩形
+type asepriteRect struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	W int `json:"w"`
+	H int `json:"h"`
+}
+
+// Aseprite导出json中的一帧
+type asepriteFrame struct {
+	// 帧在图片中的区域(裁剪后)
+	Frame asepriteRect `json:"frame"`
+	// 是否被裁剪了透明边缘
+	Trimmed bool `json:"trimmed"`
+	// 裁剪后的区域在原始帧中的位置
+	SpriteSourceSize asepriteRect `json:"spriteSourceSize"`
+	// 原始帧尺寸
+	SourceSize asepriteRect `json:"sourceSize"`
+	// 帧持续时间(毫秒)
+	Duration int `json:"duration"`
+}
+
+// Aseprite导出json中的帧标签
+type asepriteTag struct {
+	Name string `json:"name"`
+	From int    `json:"from"`
+	To   int    `json:"to"`
+	// 播放方向：forward、reverse、pingpong、pingpong_reverse
+	Direction string `json:"direction"`
+	// 重复次数，空或0表示无限循环
+	Repeat string `json:"repeat"`
+}
+
+// Aseprite导出json的根结构
+type asepriteJson struct {
+	// 帧，数组(Array)或对象(Hash)格式
+	Frames json.RawMessage `json:"frames"`
+	Meta   struct {
+		Image     string        `json:"image"`
+		FrameTags []asepriteTag `json:"frameTags"`
+	} `json:"meta"`
+}
+
+/**
+ * @brief Aseprite导出的精灵表(spritesheet)。
+ *
+ * 每个帧标签(frame tag)对应一个同名动画，帧持续时间使用Aseprite中逐帧设置的时长，
+ * 裁剪(trim)过的帧会记录在原始帧中的偏移，绘制时由SpriteComponent补偿。
+ */
+type AsepriteSheet struct {
+	// 图片路径，已经相对于json文件所在目录解析
+	Image string
+	// 全部帧，按导出顺序
+	Frames []*physics.AnimationFrame
+	// 帧标签对应的动画，没有标签时为包含全部帧的"default"动画
+	Animations []*Animation
+}
+
+/**
+ * @brief 通过虚拟文件系统加载Aseprite导出的json精灵表，支持数组与对象两种帧格式
+ * @param jsonPath json文件路径
+ * @return *AsepriteSheet 精灵表，失败返回nil
+ */
+func LoadAsepriteSheet(jsonPath string) *AsepriteSheet {
+	data, err := resource.GetVFS().ReadFile(jsonPath)
+	if err != nil {
+		slog.Error("read aseprite json failed", slog.String("path", jsonPath), slog.String("error", err.Error()))
+		return nil
+	}
+	sheet, err := ParseAsepriteSheet(data, path.Dir(jsonPath))
+	if err != nil {
+		slog.Error("parse aseprite json failed", slog.String("path", jsonPath), slog.String("error", err.Error()))
+		return nil
+	}
+	slog.Debug("aseprite sheet loaded", slog.String("path", jsonPath), slog.Int("frames", len(sheet.Frames)),
+		slog.Int("animations", len(sheet.Animations)))
+	return sheet
+}
+
+/**
+ * @brief 解析Aseprite导出的json精灵表
+ * @param data json数据
+ * @param baseDir json文件所在目录，用于解析图片路径
+ * @return *AsepriteSheet 精灵表
+ * @return error 错误
+ */
+func ParseAsepriteSheet(data []byte, baseDir string) (*AsepriteSheet, error) {
+	var root asepriteJson
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+	rawFrames, err := parseAsepriteFrames(root.Frames)
+	if err != nil {
+		return nil, err
+	}
+	if len(rawFrames) == 0 {
+		return nil, fmt.Errorf("aseprite sheet has no frames")
+	}
+
+	sheet := &AsepriteSheet{
+		Frames:     make([]*physics.AnimationFrame, 0, len(rawFrames)),
+		Animations: make([]*Animation, 0, len(root.Meta.FrameTags)),
+	}
+	if root.Meta.Image != "" {
+		sheet.Image = path.Join(baseDir, root.Meta.Image)
+	}
+	for _, raw := range rawFrames {
+		frame := &physics.AnimationFrame{
+			SourceRect: &sdl.FRect{
+				X: float32(raw.Frame.X),
+				Y: float32(raw.Frame.Y),
+				W: float32(raw.Frame.W),
+				H: float32(raw.Frame.H),
+			},
+			Duration: float64(raw.Duration) / 1000.0,
+		}
+		if raw.Trimmed {
+			frame.Offset = mgl32.Vec2{float32(raw.SpriteSourceSize.X), float32(raw.SpriteSourceSize.Y)}
+			frame.SourceSize = mgl32.Vec2{float32(raw.SourceSize.W), float32(raw.SourceSize.H)}
+		}
+		sheet.Frames = append(sheet.Frames, frame)
+	}
+
+	if len(root.Meta.FrameTags) == 0 {
+		animation := NewAnimation("default", true)
+		for _, frame := range sheet.Frames {
+			animation.addAnimationFrame(frame)
+		}
+		sheet.Animations = append(sheet.Animations, animation)
+		return sheet, nil
+	}
+	for _, tag := range root.Meta.FrameTags {
+		animation, err := sheet.newTagAnimation(tag)
+		if err != nil {
+			return nil, err
+		}
+		sheet.Animations = append(sheet.Animations, animation)
+	}
+	return sheet, nil
+}
+
+// 解析帧，数组格式直接解析，对象格式按键的出现顺序解析
+func parseAsepriteFrames(raw json.RawMessage) ([]asepriteFrame, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	if raw[0] == '[' {
+		var frames []asepriteFrame
+		err := json.Unmarshal(raw, &frames)
+		return frames, err
+	}
+
+	// 对象格式，map会丢失顺序，因此逐个token读取
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+	frames := make([]asepriteFrame, 0)
+	for decoder.More() {
+		// 帧名称
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+		var frame asepriteFrame
+		if err := decoder.Decode(&frame); err != nil {
+			return nil, err
+		}
+		frames = append(frames, frame)
+	}
+	return frames, nil
+}
+
+// 根据帧标签创建动画
+func (as *AsepriteSheet) newTagAnimation(tag asepriteTag) (*Animation, error) {
+	if tag.From < 0 || tag.To >= len(as.Frames) || tag.From > tag.To {
+		return nil, fmt.Errorf("frame tag %q range [%d, %d] out of frames", tag.Name, tag.From, tag.To)
+	}
+
+	// 按播放方向排列帧序号
+	forward := make([]int, 0, tag.To-tag.From+1)
+	for i := tag.From; i <= tag.To; i++ {
+		forward = append(forward, i)
+	}
+	reverse := make([]int, len(forward))
+	for i, index := range forward {
+		reverse[len(forward)-1-i] = index
+	}
+	var order []int
+	switch tag.Direction {
+	case "reverse":
+		order = reverse
+	case "pingpong":
+		// 往返时不重复两端的帧
+		order = append(forward, reverse[1:max(len(reverse)-1, 1)]...)
+	case "pingpong_reverse":
+		order = append(reverse, forward[1:max(len(forward)-1, 1)]...)
+	default:
+		order = forward
+	}
+	// 设置了重复次数的标签播放指定次数后停止，否则循环播放
+	repeat, _ := strconv.Atoi(tag.Repeat)
+	animation := NewAnimation(tag.Name, repeat <= 0)
+	for range max(repeat, 1) {
+		for _, index := range order {
+			animation.addAnimationFrame(as.Frames[index])
+		}
+	}
+	return animation, nil
+}
@@ -13,12 +13,28 @@ import (
 const (
 	// 相机移动距离阈值，低于该值时，相机位置直接设置到目标位置
 	SNAP_THRESHOLD = float32(1.0)
+	// 目标水平速度超过该值(像素/秒)时才更新朝向
+	LOOK_AHEAD_MIN_SPEED = float32(10.0)
+)
+
+// 相机垂直跟随方式
+type CameraVerticalMode int
+
+const (
+	// 与水平方向相同，目标离开死区即跟随
+	CameraVerticalFollow CameraVerticalMode = iota
+	// 只在目标落地时重新居中，目标离开死区时仍然跟随，跳跃时画面不会上下晃动
+	CameraVerticalOnLanding
 )
 
 // 相机
 type Camera struct {
 	// 视口大小(屏幕大小)
 	viewportSize mgl32.Vec2
+	// 视口左上角在屏幕(逻辑分辨率)中的位置，分屏或画中画时不为0
+	viewportPosition mgl32.Vec2
+	// 视口背景色，nil表示绘制前不填充视口(全屏相机由清屏处理)
+	backgroundColor *emath.FColor
 	// 相机左上角的世界坐标
 	position mgl32.Vec2
 	// 限制相机在世界中的移动范围，nil表示不限制
@@ -27,6 +43,42 @@ type Camera struct {
 	targetTC physics.ITransformComponent
 	// 相机平滑移动速度
 	smoothSpeed float32
+	// 死区大小(以视口中心为中心)，目标在死区内移动时相机不动，0表示始终居中
+	deadzone mgl32.Vec2
+	// 相机关注点(世界坐标)，即不考虑前视偏移时视口中心应该所在的位置
+	focus mgl32.Vec2
+	// 关注点是否已经初始化
+	hasFocus bool
+	// 前视距离，相机向目标朝向偏移的最大像素
+	lookAheadDistance float32
+	// 前视偏移的平滑速度
+	lookAheadSpeed float32
+	// 当前前视偏移
+	lookAhead float32
+	// 目标朝向，1向右，-1向左，0未知
+	facing float32
+	// 上一帧目标位置，用于估算目标速度
+	lastTargetPos mgl32.Vec2
+	// 垂直跟随方式
+	verticalMode CameraVerticalMode
+	// 判断目标是否着地，CameraVerticalOnLanding使用，nil视为始终着地
+	isTargetGrounded func() bool
+	// 缩放倍数，大于1放大(看到的世界范围变小)
+	zoom float32
+	// 缩放动画，nil表示没有进行中的缩放
+	zoomTween *cameraZoomTween
+	// 震动与冲击
+	shake cameraShake
+	// 脚本控制的平移队列，不为空时相机不跟随目标
+	pans []*cameraPan
+	// 相机区域，跟随目标进入区域时限制范围过渡到区域范围
+	zones []CameraZone
+	// 目标所在的区域，nil表示不在任何区域内
+	currentZone *CameraZone
+	// 不在任何区域内时的限制范围
+	defaultBounds *emath.Rect
+	// 限制范围过渡动画，nil表示没有进行中的过渡
+	boundsTween *cameraBoundsTween
 }
 
 // 确保Camera实现了ICamera接口
@@ -40,6 +92,8 @@ func NewCamera(viewportSize, position mgl32.Vec2, limitBounds *emath.Rect) *Came
 		position:     position,
 		limitBounds:  limitBounds,
 		smoothSpeed:  5.0,
+		zoom:         1.0,
+		shake:        newCameraShake(),
 	}
 }
 
@@ -51,14 +105,22 @@ func (c *Camera) SetPosition(position mgl32.Vec2) {
 
 // 更新
 func (c *Camera) Update(deltaTime float64) {
+	c.updateZoom(deltaTime)
+	c.updateZones(deltaTime)
+	c.shake.update(deltaTime)
+	// 脚本平移期间不跟随目标
+	if c.updatePan(deltaTime) {
+		return
+	}
 	if c.targetTC == nil {
 		return
 	}
 
 	// 计算相机目标位置
 	targetTCPos := c.targetTC.GetPosition()
-	// 目标向量与视口中心向量差值
-	desiredPos := targetTCPos.Sub(c.viewportSize.Mul(0.5))
+	c.updateFocus(targetTCPos, deltaTime)
+	// 关注点加上前视偏移即为视口中心
+	desiredPos := c.focus.Add(mgl32.Vec2{c.lookAhead, 0.0}).Sub(c.GetViewSize().Mul(0.5))
 	// 计算相机当前位置与想要去的位置差值
 	distance := c.position.Sub(desiredPos).Len()
 
@@ -82,56 +144,160 @@ func (c *Camera) Update(deltaTime float64) {
 	c.ClampPosition()
 }
 
+// 根据目标位置更新关注点与前视偏移
+func (c *Camera) updateFocus(targetPos mgl32.Vec2, deltaTime float64) {
+	if !c.hasFocus {
+		c.focus = targetPos
+		c.lastTargetPos = targetPos
+		c.hasFocus = true
+	}
+
+	// 水平方向：目标离开死区时，关注点被推动到目标恰好位于死区边缘
+	halfDeadzone := c.deadzone.Mul(0.5)
+	c.focus[0] = pushIntoDeadzone(c.focus.X(), targetPos.X(), halfDeadzone.X())
+
+	// 垂直方向
+	switch c.verticalMode {
+	case CameraVerticalOnLanding:
+		if c.isTargetGrounded == nil || c.isTargetGrounded() {
+			// 着地后重新居中，平滑由位置插值完成
+			c.focus[1] = targetPos.Y()
+		} else {
+			// 空中只在目标离开死区时跟随，避免目标离开画面
+			c.focus[1] = pushIntoDeadzone(c.focus.Y(), targetPos.Y(), halfDeadzone.Y())
+		}
+	default:
+		c.focus[1] = pushIntoDeadzone(c.focus.Y(), targetPos.Y(), halfDeadzone.Y())
+	}
+
+	// 前视：根据估算的水平速度确定朝向，相机向朝向偏移
+	if c.lookAheadDistance > 0.0 && deltaTime > 0.0 {
+		velocityX := (targetPos.X() - c.lastTargetPos.X()) / float32(deltaTime)
+		if velocityX > LOOK_AHEAD_MIN_SPEED {
+			c.facing = 1.0
+		} else if velocityX < -LOOK_AHEAD_MIN_SPEED {
+			c.facing = -1.0
+		}
+		t := min(c.lookAheadSpeed*float32(deltaTime), 1.0)
+		c.lookAhead += (c.facing*c.lookAheadDistance - c.lookAhead) * t
+	}
+	c.lastTargetPos = targetPos
+}
+
+// 将关注点推动到目标恰好位于死区内，halfSize为死区的一半
+func pushIntoDeadzone(focus, target, halfSize float32) float32 {
+	if target > focus+halfSize {
+		return target - halfSize
+	}
+	if target < focus-halfSize {
+		return target + halfSize
+	}
+	return focus
+}
+
+/**
+ * @brief 设置死区，目标在死区内移动时相机不动
+ * @param deadzone 死区大小(像素)，以视口中心为中心，0表示始终居中
+ */
+func (c *Camera) SetDeadzone(deadzone mgl32.Vec2) {
+	c.deadzone = deadzone
+}
+
+// 获取死区大小
+func (c *Camera) GetDeadzone() mgl32.Vec2 {
+	return c.deadzone
+}
+
+/**
+ * @brief 设置前视，相机向目标的移动方向偏移，让玩家看到前方更多内容
+ * @param distance 最大偏移像素，0表示关闭
+ * @param speed 偏移的平滑速度，越大越快
+ */
+func (c *Camera) SetLookAhead(distance, speed float32) {
+	c.lookAheadDistance = distance
+	c.lookAheadSpeed = speed
+	if distance <= 0.0 {
+		c.lookAhead = 0.0
+	}
+}
+
+/**
+ * @brief 设置垂直跟随方式
+ * @param mode 跟随方式
+ * @param isTargetGrounded 判断目标是否着地，CameraVerticalOnLanding使用
+ */
+func (c *Camera) SetVerticalMode(mode CameraVerticalMode, isTargetGrounded func() bool) {
+	c.verticalMode = mode
+	c.isTargetGrounded = isTargetGrounded
+}
+
+// 重置取景参数为默认值(始终居中，无前视，垂直直接跟随)
+func (c *Camera) ResetFraming() {
+	c.deadzone = mgl32.Vec2{0.0, 0.0}
+	c.lookAheadDistance = 0.0
+	c.lookAhead = 0.0
+	c.facing = 0.0
+	c.verticalMode = CameraVerticalFollow
+	c.isTargetGrounded = nil
+}
+
 // 移动相机
 func (c *Camera) Move(direction mgl32.Vec2) {
 	c.position = c.position.Add(direction)
 	c.ClampPosition()
 }
 
-// 设置相机限制范围
+// 设置相机限制范围，立即生效并取消进行中的过渡
 func (c *Camera) SetLimitBounds(limitBounds *math.Rect) {
+	c.boundsTween = nil
 	c.limitBounds = limitBounds
 	c.ClampPosition()
 }
 
-// 获取相机位置
+// 获取相机位置(视口左上角的世界坐标)，包含震动与冲击偏移，绘制及可见范围计算都以此为准
+// 加上偏移后同样限制在限制范围内，边缘处的震动不会露出范围外的区域
 func (c *Camera) GetPosition() mgl32.Vec2 {
-	return c.position
+	return c.clamp(c.position.Add(c.shake.offset))
 }
 
 // 限制相机位置在限制范围内
 func (c *Camera) ClampPosition() {
+	c.position = c.clamp(c.position)
+}
+
+// 把视口左上角位置限制在限制范围内，没有有效的限制范围时原样返回
+func (c *Camera) clamp(position mgl32.Vec2) mgl32.Vec2 {
 	if c.limitBounds == nil || c.limitBounds.Size.X() <= 0.0 || c.limitBounds.Size.Y() <= 0.0 {
-		return
+		return position
 	}
 
 	// 计算允许相机移动位置范围
 	minCamPos := c.limitBounds.Position
-	maxCamPos := c.limitBounds.Position.Add(c.limitBounds.Size).Sub(c.viewportSize)
+	maxCamPos := c.limitBounds.Position.Add(c.limitBounds.Size).Sub(c.GetViewSize())
 
 	// 确保maxCamPos不小于minCamPos，视口可能比世界还大
 	maxCamPos[0] = max(maxCamPos.X(), minCamPos.X())
 	maxCamPos[1] = max(maxCamPos.Y(), minCamPos.Y())
 
 	// 限制相机位置在范围内
-	c.position = math.Mgl32Vec2Clamp(c.position, minCamPos, maxCamPos)
+	return math.Mgl32Vec2Clamp(position, minCamPos, maxCamPos)
 }
 
 // 世界坐标转换为屏幕坐标(视口坐标)
 func (c *Camera) WorldToScreen(worldPos mgl32.Vec2) mgl32.Vec2 {
-	return worldPos.Sub(c.position)
+	return worldPos.Sub(c.GetPosition()).Mul(c.zoom)
 }
 
 // 世界坐标转换为屏幕坐标(视口坐标)，考虑视差
 // scrollFactor，视差系数，用于计算视差效果，0.0表示没有视差(固定背景)，1.0表示背景跟着相机移动，0.0~1.0之间视差
 // 移动得越快，看起来离玩家越近, 视差系数越接近1.0。移动得越慢，看起来离玩家越远，视差系数越接近0.0
 func (c *Camera) WorldToScreenWithParallax(worldPos mgl32.Vec2, scrollFactor mgl32.Vec2) mgl32.Vec2 {
-	return worldPos.Sub(math.Mgl32Vec2MulElem(c.position, scrollFactor))
+	return worldPos.Sub(math.Mgl32Vec2MulElem(c.GetPosition(), scrollFactor)).Mul(c.zoom)
 }
 
-// 屏幕坐标(视口坐标)转换为世界坐标
+// 屏幕坐标(视口坐标，相对于视口左上角)转换为世界坐标
 func (c *Camera) ScreenToWorld(screenPos mgl32.Vec2) mgl32.Vec2 {
-	return screenPos.Add(c.position)
+	return screenPos.Mul(1.0 / c.zoom).Add(c.GetPosition())
 }
 
 // 获取相机视口大小(屏幕大小)
@@ -139,14 +305,54 @@ func (c *Camera) GetViewportSize() mgl32.Vec2 {
 	return c.viewportSize
 }
 
+/**
+ * @brief 设置视口在屏幕中的区域，用于分屏或画中画
+ *
+ * 屏幕坐标(WorldToScreen的结果)是相对于视口左上角的坐标。
+ * @param rect 视口区域(逻辑分辨率坐标)
+ */
+func (c *Camera) SetViewport(rect emath.Rect) {
+	c.viewportPosition = rect.Position
+	c.viewportSize = rect.Size
+	c.ClampPosition()
+}
+
+// 获取视口在屏幕中的区域
+func (c *Camera) GetViewportRect() emath.Rect {
+	return emath.Rect{Position: c.viewportPosition, Size: c.viewportSize}
+}
+
+// 设置视口背景色，nil表示不填充
+func (c *Camera) SetBackgroundColor(color *emath.FColor) {
+	c.backgroundColor = color
+}
+
+// 获取视口背景色
+func (c *Camera) GetBackgroundColor() *emath.FColor {
+	return c.backgroundColor
+}
+
+// 获取视口在世界中的尺寸，即视口大小除以缩放倍数
+func (c *Camera) GetViewSize() mgl32.Vec2 {
+	return c.viewportSize.Mul(1.0 / c.zoom)
+}
+
+// 获取缩放倍数
+func (c *Camera) GetZoom() float32 {
+	return c.zoom
+}
+
 // 获取相机限制范围
 func (c *Camera) GetLimitBounds() *math.Rect {
 	return c.limitBounds
 }
 
-// 设置相机跟随目标
+// 设置相机跟随目标，关注点在下一次更新时重新初始化
 func (c *Camera) SetTargetTC(targetTC physics.ITransformComponent) {
 	c.targetTC = targetTC
+	c.hasFocus = false
+	c.lookAhead = 0.0
+	c.facing = 0.0
 }
 
 // 获取相机跟随目标
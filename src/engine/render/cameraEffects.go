@@ -0,0 +1,264 @@
+package render
+
+import (
+	"log/slog"
+	"math"
+	"math/rand/v2"
+
+	emath "sunny_land/src/engine/utils/math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	// 缩放倍数范围
+	CAMERA_MIN_ZOOM = float32(0.25)
+	CAMERA_MAX_ZOOM = float32(4.0)
+)
+
+/**
+ * @brief 相机震动与冲击。
+ *
+ * 震动基于创伤值(trauma)：每次受到冲击增加创伤值，创伤值随时间线性衰减，
+ * 震动幅度为创伤值的平方乘以最大偏移，因此小冲击几乎不可察觉，大冲击明显。
+ * 冲击(kick)是一次定向的位移，随时间指数衰减回0，例如踩踏敌人时画面向下一顿。
+ */
+type cameraShake struct {
+	// 创伤值，0.0~1.0
+	trauma float32
+	// 每秒衰减的创伤值
+	decay float32
+	// 创伤值为1时的最大偏移(像素)
+	maxOffset mgl32.Vec2
+	// 当前冲击位移
+	kick mgl32.Vec2
+	// 冲击位移的衰减速度，越大回弹越快
+	kickDecay float32
+	// 当前总偏移(震动 + 冲击)
+	offset mgl32.Vec2
+}
+
+// 创建默认参数的震动
+func newCameraShake() cameraShake {
+	return cameraShake{
+		decay:     1.5,
+		maxOffset: mgl32.Vec2{8.0, 6.0},
+		kickDecay: 12.0,
+	}
+}
+
+// 更新震动偏移
+func (cs *cameraShake) update(deltaTime float64) {
+	dt := float32(deltaTime)
+	cs.trauma = max(cs.trauma-cs.decay*dt, 0.0)
+	cs.kick = cs.kick.Mul(float32(math.Exp(float64(-cs.kickDecay * dt))))
+	if cs.kick.Len() < 0.1 {
+		cs.kick = mgl32.Vec2{0.0, 0.0}
+	}
+
+	shake := cs.trauma * cs.trauma
+	cs.offset = cs.kick.Add(mgl32.Vec2{
+		cs.maxOffset.X() * shake * (rand.Float32()*2.0 - 1.0),
+		cs.maxOffset.Y() * shake * (rand.Float32()*2.0 - 1.0),
+	})
+	// 取整一下，要不画面撕裂
+	cs.offset = mgl32.Vec2{mgl32.Round(cs.offset.X(), 0), mgl32.Round(cs.offset.Y(), 0)}
+}
+
+// 增加创伤值，amount为0.0~1.0，结果不超过1.0
+func (c *Camera) AddTrauma(amount float32) {
+	c.shake.trauma = emath.Clamp(c.shake.trauma+amount, 0.0, 1.0)
+}
+
+// 获取当前创伤值
+func (c *Camera) GetTrauma() float32 {
+	return c.shake.trauma
+}
+
+/**
+ * @brief 设置震动参数
+ * @param maxOffset 创伤值为1时的最大偏移(像素)
+ * @param decay 每秒衰减的创伤值
+ */
+func (c *Camera) SetShake(maxOffset mgl32.Vec2, decay float32) {
+	c.shake.maxOffset = maxOffset
+	c.shake.decay = decay
+}
+
+/**
+ * @brief 冲击相机，画面向指定方向位移后回弹
+ * @param impulse 位移(像素)，例如{0, 4}表示向下一顿
+ * @param trauma 同时增加的创伤值，0表示不震动
+ */
+func (c *Camera) Kick(impulse mgl32.Vec2, trauma float32) {
+	c.shake.kick = c.shake.kick.Add(impulse)
+	if trauma > 0.0 {
+		c.AddTrauma(trauma)
+	}
+}
+
+// 立即停止所有震动与冲击
+func (c *Camera) StopShake() {
+	c.shake.trauma = 0.0
+	c.shake.kick = mgl32.Vec2{0.0, 0.0}
+	c.shake.offset = mgl32.Vec2{0.0, 0.0}
+}
+
+// 缩放动画
+type cameraZoomTween struct {
+	from, to float32
+	duration float64
+	elapsed  float64
+	ease     emath.EaseFunc
+}
+
+/**
+ * @brief 立即设置缩放倍数，以视口中心为缩放中心
+ * @param zoom 缩放倍数，限制在CAMERA_MIN_ZOOM~CAMERA_MAX_ZOOM之间
+ */
+func (c *Camera) SetZoom(zoom float32) {
+	c.zoomTween = nil
+	c.applyZoom(zoom)
+}
+
+/**
+ * @brief 在一段时间内缓动到指定缩放倍数
+ * @param zoom 目标缩放倍数
+ * @param duration 时长(秒)，不大于0时立即设置
+ * @param ease 缓动函数，nil表示线性
+ */
+func (c *Camera) ZoomTo(zoom float32, duration float64, ease emath.EaseFunc) {
+	if duration <= 0.0 {
+		c.SetZoom(zoom)
+		return
+	}
+	if ease == nil {
+		ease = emath.EaseLinear
+	}
+	c.zoomTween = &cameraZoomTween{from: c.zoom, to: zoom, duration: duration, ease: ease}
+}
+
+// 设置缩放倍数并保持视口中心不变
+func (c *Camera) applyZoom(zoom float32) {
+	zoom = emath.Clamp(zoom, CAMERA_MIN_ZOOM, CAMERA_MAX_ZOOM)
+	center := c.position.Add(c.GetViewSize().Mul(0.5))
+	c.zoom = zoom
+	c.position = center.Sub(c.GetViewSize().Mul(0.5))
+	c.ClampPosition()
+}
+
+// 更新缩放动画
+func (c *Camera) updateZoom(deltaTime float64) {
+	if c.zoomTween == nil {
+		return
+	}
+	tween := c.zoomTween
+	tween.elapsed += deltaTime
+	t := float32(min(tween.elapsed/tween.duration, 1.0))
+	c.applyZoom(tween.from + (tween.to-tween.from)*tween.ease(t))
+	if t >= 1.0 {
+		c.zoomTween = nil
+	}
+}
+
+// 脚本控制的一段平移
+type cameraPan struct {
+	// 目标视口中心(世界坐标)
+	to mgl32.Vec2
+	// 起点视口中心，开始时记录
+	from mgl32.Vec2
+	// 是否已经开始
+	started bool
+	// 移动时长与到达后的停留时长(秒)
+	duration, hold float64
+	// 已经经过的时间
+	elapsed float64
+	// 缓动函数
+	ease emath.EaseFunc
+	// 完成(停留结束)时的回调，可以为nil
+	onComplete func()
+}
+
+/**
+ * @brief 将相机平移到指定位置，用于过场或展示关卡元素。
+ *
+ * 多次调用按顺序依次执行，全部完成后相机恢复跟随目标(平滑移动回去)。
+ * @param center 目标视口中心(世界坐标)
+ * @param duration 移动时长(秒)
+ * @param hold 到达后的停留时长(秒)
+ * @param ease 缓动函数，nil表示先加速后减速
+ * @param onComplete 停留结束时的回调，可以为nil
+ */
+func (c *Camera) PanTo(center mgl32.Vec2, duration, hold float64, ease emath.EaseFunc, onComplete func()) {
+	if ease == nil {
+		ease = emath.EaseInOutQuad
+	}
+	c.pans = append(c.pans, &cameraPan{
+		to:         center,
+		duration:   max(duration, 0.0),
+		hold:       max(hold, 0.0),
+		ease:       ease,
+		onComplete: onComplete,
+	})
+	slog.Debug("camera pan queued", slog.Any("center", center), slog.Float64("duration", duration), slog.Float64("hold", hold))
+}
+
+// 是否正在执行脚本平移
+func (c *Camera) IsPanning() bool {
+	return len(c.pans) > 0
+}
+
+// 取消所有脚本平移，立即恢复跟随目标，未完成的回调不会被调用
+func (c *Camera) CancelPans() {
+	c.pans = nil
+	c.hasFocus = false
+}
+
+/**
+ * @brief 更新脚本平移
+ * @return bool 是否正在平移，正在平移时不跟随目标
+ */
+func (c *Camera) updatePan(deltaTime float64) bool {
+	if len(c.pans) == 0 {
+		return false
+	}
+	pan := c.pans[0]
+	halfView := c.GetViewSize().Mul(0.5)
+	if !pan.started {
+		pan.from = c.position.Add(halfView)
+		pan.started = true
+	}
+
+	pan.elapsed += deltaTime
+	t := float32(1.0)
+	if pan.duration > 0.0 {
+		t = float32(min(pan.elapsed/pan.duration, 1.0))
+	}
+	center := emath.Mgl32Vec2Mix(pan.from, pan.to, pan.ease(t))
+	c.position = mgl32.Vec2{
+		mgl32.Round(center.X()-halfView.X(), 0),
+		mgl32.Round(center.Y()-halfView.Y(), 0),
+	}
+	c.ClampPosition()
+
+	if pan.elapsed < pan.duration+pan.hold {
+		return true
+	}
+	c.pans = c.pans[1:]
+	if pan.onComplete != nil {
+		pan.onComplete()
+	}
+	if len(c.pans) == 0 {
+		// 交还控制权，关注点在下一次更新时重新初始化到目标位置，相机平滑移动回去
+		c.hasFocus = false
+		slog.Debug("camera pans finished, resume following target")
+	}
+	return true
+}
+
+// 重置所有效果：停止震动、取消脚本平移、恢复原始缩放
+func (c *Camera) ResetEffects() {
+	c.StopShake()
+	c.CancelPans()
+	c.SetZoom(1.0)
+}
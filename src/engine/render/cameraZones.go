@@ -0,0 +1,125 @@
+package render
+
+import (
+	"log/slog"
+
+	emath "sunny_land/src/engine/utils/math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// 相机区域切换时限制范围的默认过渡时长(秒)
+const CAMERA_ZONE_TRANSITION = 0.5
+
+/**
+ * @brief 相机区域，跟随目标进入区域后相机的限制范围过渡到区域范围。
+ *
+ * 用于银河城式的房间或锁定的Boss战场地。区域可以重叠，目标仍在当前区域内时不会切换，
+ * 离开当前区域后选择包含目标的第一个区域，不在任何区域内时使用默认限制范围。
+ */
+type CameraZone struct {
+	// 区域名称
+	Name string
+	// 区域范围(世界坐标)，同时也是相机的限制范围
+	Bounds emath.Rect
+	// 进入区域时限制范围的过渡时长(秒)
+	Transition float64
+}
+
+// 判断点是否在区域内
+func (cz *CameraZone) Contains(point mgl32.Vec2) bool {
+	return point.X() >= cz.Bounds.Position.X() && point.X() < cz.Bounds.Position.X()+cz.Bounds.Size.X() &&
+		point.Y() >= cz.Bounds.Position.Y() && point.Y() < cz.Bounds.Position.Y()+cz.Bounds.Size.Y()
+}
+
+// 限制范围过渡动画
+type cameraBoundsTween struct {
+	from, to emath.Rect
+	duration float64
+	elapsed  float64
+}
+
+/**
+ * @brief 设置相机区域
+ * @param zones 区域列表，nil表示不使用区域
+ * @param defaultBounds 目标不在任何区域内时的限制范围，nil表示不限制
+ */
+func (c *Camera) SetZones(zones []CameraZone, defaultBounds *emath.Rect) {
+	c.zones = zones
+	c.defaultBounds = defaultBounds
+	c.currentZone = nil
+	c.boundsTween = nil
+	c.SetLimitBounds(defaultBounds)
+	// 根据目标当前位置立即确定区域，不过渡
+	if zone := c.findZone(); zone != nil {
+		c.currentZone = zone
+		bounds := zone.Bounds
+		c.SetLimitBounds(&bounds)
+	}
+}
+
+// 获取目标所在的相机区域，nil表示不在任何区域内
+func (c *Camera) GetCurrentZone() *CameraZone {
+	return c.currentZone
+}
+
+/**
+ * @brief 在一段时间内将限制范围平滑过渡到新的范围
+ * @param bounds 新的限制范围，nil表示不限制(立即生效)
+ * @param duration 过渡时长(秒)，不大于0时立即生效
+ */
+func (c *Camera) TransitionLimitBounds(bounds *emath.Rect, duration float64) {
+	if bounds == nil || c.limitBounds == nil || duration <= 0.0 {
+		c.SetLimitBounds(bounds)
+		return
+	}
+	// 从当前限制范围开始过渡，避免限制范围突变导致画面跳动
+	c.boundsTween = &cameraBoundsTween{from: *c.limitBounds, to: *bounds, duration: duration}
+}
+
+// 查找目标所在的区域，仍在当前区域内时优先保持当前区域
+func (c *Camera) findZone() *CameraZone {
+	if c.targetTC == nil || len(c.zones) == 0 {
+		return nil
+	}
+	targetPos := c.targetTC.GetPosition()
+	if c.currentZone != nil && c.currentZone.Contains(targetPos) {
+		return c.currentZone
+	}
+	for i := range c.zones {
+		if c.zones[i].Contains(targetPos) {
+			return &c.zones[i]
+		}
+	}
+	return nil
+}
+
+// 更新目标所在区域及限制范围过渡
+func (c *Camera) updateZones(deltaTime float64) {
+	if zone := c.findZone(); len(c.zones) > 0 && zone != c.currentZone {
+		c.currentZone = zone
+		if zone != nil {
+			bounds := zone.Bounds
+			slog.Debug("camera enter zone", slog.String("zone", zone.Name), slog.Any("bounds", bounds))
+			c.TransitionLimitBounds(&bounds, zone.Transition)
+		} else {
+			slog.Debug("camera leave zones, use default bounds")
+			c.TransitionLimitBounds(c.defaultBounds, CAMERA_ZONE_TRANSITION)
+		}
+	}
+
+	if c.boundsTween == nil {
+		return
+	}
+	tween := c.boundsTween
+	tween.elapsed += deltaTime
+	t := emath.EaseInOutQuad(float32(min(tween.elapsed/tween.duration, 1.0)))
+	c.limitBounds = &emath.Rect{
+		Position: emath.Mgl32Vec2Mix(tween.from.Position, tween.to.Position, t),
+		Size:     emath.Mgl32Vec2Mix(tween.from.Size, tween.to.Size, t),
+	}
+	if tween.elapsed >= tween.duration {
+		c.boundsTween = nil
+		c.limitBounds = &tween.to
+	}
+}
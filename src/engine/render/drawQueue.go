@@ -0,0 +1,219 @@
+package render
+
+import (
+	"log/slog"
+	"math"
+	"sort"
+
+	emath "sunny_land/src/engine/utils/math"
+
+	"github.com/SunshineZzzz/purego-sdl3/sdl"
+)
+
+// 默认绘制图层，未指定时使用
+const DrawLayerDefault = 0
+
+// 绘制命令，一个带纹理的四边形
+type drawCommand struct {
+	// 图层，越大越靠前
+	layer int
+	// 图层内的排序键，越大越靠前，例如按y坐标排序
+	sortKey float32
+	// 提交顺序，图层与排序键相同时按提交顺序绘制
+	seq int
+	// 纹理
+	texture *sdl.Texture
+	// 源矩形(纹理坐标系)
+	srcRect sdl.FRect
+	// 目标矩形(屏幕坐标系)
+	dstRect sdl.FRect
+	// 旋转角度(度)，绕目标矩形中心顺时针旋转
+	angle float64
+	// 是否水平反转
+	isFlipped bool
+	// 颜色调制，提交时与渲染器的颜色调制相乘
+	color emath.FColor
+	// 混合模式
+	blendMode sdl.BlendMode
+}
+
+/**
+ * @brief 开始收集绘制命令，之后的精灵图绘制不会立即执行，直到FlushQueue时按图层排序后批量绘制。
+ *
+ * 未调用BeginQueue时精灵图立即绘制(等价于只有一个命令的队列)。
+ */
+func (r *Renderer) BeginQueue() {
+	if r.queueing {
+		slog.Warn("draw queue already begun, flush pending commands")
+		r.FlushQueue()
+	}
+	r.queueing = true
+}
+
+// 按图层、排序键、提交顺序排序后绘制所有命令，并结束收集
+func (r *Renderer) FlushQueue() {
+	r.queueing = false
+	if len(r.queue) == 0 {
+		return
+	}
+	sort.SliceStable(r.queue, func(i, j int) bool {
+		a, b := &r.queue[i], &r.queue[j]
+		if a.layer != b.layer {
+			return a.layer < b.layer
+		}
+		if a.sortKey != b.sortKey {
+			return a.sortKey < b.sortKey
+		}
+		return a.seq < b.seq
+	})
+	r.drawCommands(r.queue)
+	r.queue = r.queue[:0]
+	r.seq = 0
+}
+
+/**
+ * @brief 设置之后绘制的精灵图的图层与排序键
+ * @param layer 图层，越大越靠前
+ * @param sortKey 图层内的排序键，越大越靠前
+ */
+func (r *Renderer) SetDrawOrder(layer int, sortKey float32) {
+	r.drawLayer = layer
+	r.sortKey = sortKey
+}
+
+// 重置图层与排序键为默认值
+func (r *Renderer) ResetDrawOrder() {
+	r.drawLayer = DrawLayerDefault
+	r.sortKey = 0.0
+}
+
+// 获取上一帧的绘制统计：精灵图数量、绘制调用次数
+func (r *Renderer) GetFrameStats() (int, int) {
+	return r.lastSpriteCount, r.lastDrawCalls
+}
+
+// 开始新的一帧的统计
+func (r *Renderer) resetFrameStats() {
+	r.lastSpriteCount, r.lastDrawCalls = r.spriteCount, r.drawCalls
+	r.spriteCount, r.drawCalls = 0, 0
+}
+
+// 提交绘制命令，收集中则加入队列，否则立即绘制
+func (r *Renderer) submit(cmd drawCommand) {
+	cmd.layer = r.drawLayer
+	cmd.sortKey = r.sortKey
+	cmd.color = mulColor(r.colorMod, cmd.color)
+	// 预乘alpha的纹理，颜色调制的alpha(例如图层不透明度)同样需要乘到RGB上
+	if cmd.blendMode == sdl.BlendModeBlendPremultiplied {
+		cmd.color.R *= cmd.color.A
+		cmd.color.G *= cmd.color.A
+		cmd.color.B *= cmd.color.A
+	}
+	r.spriteCount++
+	if !r.queueing {
+		r.drawCommands([]drawCommand{cmd})
+		return
+	}
+	cmd.seq = r.seq
+	r.seq++
+	r.queue = append(r.queue, cmd)
+}
+
+// 颜色分量相乘
+func mulColor(a, b emath.FColor) emath.FColor {
+	return emath.FColor{R: a.R * b.R, G: a.G * b.G, B: a.B * b.B, A: a.A * b.A}
+}
+
+// 绘制命令，相邻的同纹理、同混合模式命令合并为一次RenderGeometry调用
+func (r *Renderer) drawCommands(cmds []drawCommand) {
+	start := 0
+	for i := 1; i <= len(cmds); i++ {
+		if i < len(cmds) && cmds[i].texture == cmds[start].texture && cmds[i].blendMode == cmds[start].blendMode {
+			continue
+		}
+		r.drawBatch(cmds[start:i])
+		start = i
+	}
+}
+
+// 绘制一批同纹理的命令
+func (r *Renderer) drawBatch(cmds []drawCommand) {
+	texture := cmds[0].texture
+	var texW, texH float32
+	if !sdl.GetTextureSize(texture, &texW, &texH) || texW <= 0.0 || texH <= 0.0 {
+		slog.Error("get texture size failed", slog.String("error", sdl.GetError()))
+		return
+	}
+	// 颜色调制通过顶点颜色实现，纹理本身的调制需要是白色
+	r.resetTextureMod(texture)
+	// 纹理是共享的，混合模式只在本批次内生效
+	blendMode := cmds[0].blendMode
+	if blendMode != sdl.BlendModeBlend {
+		r.setTextureBlendMode(texture, blendMode)
+		defer r.setTextureBlendMode(texture, sdl.BlendModeBlend)
+	}
+
+	r.vertices = r.vertices[:0]
+	r.indices = r.indices[:0]
+	for i := range cmds {
+		r.appendQuad(&cmds[i], texW, texH)
+	}
+	if !sdl.RenderGeometry(r.sdlRenderer, texture, r.vertices, r.indices) {
+		slog.Error("render geometry failed", slog.Int("quads", len(cmds)), slog.String("error", sdl.GetError()))
+	}
+	r.drawCalls++
+}
+
+// 将命令转化为四个顶点、两个三角形
+func (r *Renderer) appendQuad(cmd *drawCommand, texW, texH float32) {
+	// 纹理坐标，水平反转时交换左右
+	u0, u1 := cmd.srcRect.X/texW, (cmd.srcRect.X+cmd.srcRect.W)/texW
+	v0, v1 := cmd.srcRect.Y/texH, (cmd.srcRect.Y+cmd.srcRect.H)/texH
+	if cmd.isFlipped {
+		u0, u1 = u1, u0
+	}
+
+	// 顶点相对于中心的坐标，左上、右上、右下、左下
+	halfW, halfH := cmd.dstRect.W*0.5, cmd.dstRect.H*0.5
+	centerX, centerY := cmd.dstRect.X+halfW, cmd.dstRect.Y+halfH
+	corners := [4]sdl.FPoint{{X: -halfW, Y: -halfH}, {X: halfW, Y: -halfH}, {X: halfW, Y: halfH}, {X: -halfW, Y: halfH}}
+	if cmd.angle != 0.0 {
+		// 屏幕坐标y轴向下，标准旋转公式即为顺时针旋转
+		sin, cos := math.Sincos(cmd.angle * math.Pi / 180.0)
+		for i, c := range corners {
+			corners[i] = sdl.FPoint{
+				X: c.X*float32(cos) - c.Y*float32(sin),
+				Y: c.X*float32(sin) + c.Y*float32(cos),
+			}
+		}
+	}
+	uvs := [4]sdl.FPoint{{X: u0, Y: v0}, {X: u1, Y: v0}, {X: u1, Y: v1}, {X: u0, Y: v1}}
+	color := sdl.FColor{R: cmd.color.R, G: cmd.color.G, B: cmd.color.B, A: cmd.color.A}
+
+	base := int32(len(r.vertices))
+	for i := range corners {
+		r.vertices = append(r.vertices, sdl.Vertex{
+			Position: sdl.FPoint{X: centerX + corners[i].X, Y: centerY + corners[i].Y},
+			Color:    color,
+			TexCoord: uvs[i],
+		})
+	}
+	r.indices = append(r.indices, base, base+1, base+2, base, base+2, base+3)
+}
+
+// 重置纹理的颜色调制为白色不透明
+func (r *Renderer) resetTextureMod(texture *sdl.Texture) {
+	if !sdl.SetTextureColorModFloat(texture, 1.0, 1.0, 1.0) {
+		slog.Error("set texture color mod failed", slog.String("error", sdl.GetError()))
+	}
+	if !sdl.SetTextureAlphaModFloat(texture, 1.0) {
+		slog.Error("set texture alpha mod failed", slog.String("error", sdl.GetError()))
+	}
+}
+
+// 设置纹理的混合模式
+func (r *Renderer) setTextureBlendMode(texture *sdl.Texture, blendMode sdl.BlendMode) {
+	if !sdl.SetTextureBlendMode(texture, blendMode) {
+		slog.Error("set texture blend mode failed", slog.Any("blendMode", blendMode), slog.String("error", sdl.GetError()))
+	}
+}
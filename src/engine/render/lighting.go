@@ -0,0 +1,146 @@
+package render
+
+import (
+	"log/slog"
+
+	"sunny_land/src/engine/physics"
+	emath "sunny_land/src/engine/utils/math"
+
+	"github.com/SunshineZzzz/purego-sdl3/sdl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+/**
+ * @brief 2D光照，只使用SDL_Renderer的混合模式，不需要着色器。
+ *
+ * 1. BeginLighting后，光源精灵图(径向渐变或任意形状的cookie纹理)通过DrawLight收集到光源列表
+ * 2. EndLighting时先将暗度层(视口大小的渲染目标)填充为环境光颜色，
+ *    再以加法混合(BlendModeAdd)绘制所有光源，光源颜色的A为强度
+ * 3. 最后以调制混合(BlendModeMod，结果 = 场景颜色 * 暗度层颜色)将暗度层合成到场景上
+ * 环境光为白色时场景不变，为黑色时只有光源照亮的部分可见。
+ */
+type lighting struct {
+	// 是否正在收集光源
+	active bool
+	// 本次收集的光源
+	lights []drawCommand
+	// 暗度层渲染目标，尺寸跟随视口变化
+	target *sdl.Texture
+	// 暗度层尺寸
+	targetW, targetH int32
+}
+
+// 开始收集光源，之后DrawLight提交的光源在EndLighting时合成，未开始时DrawLight被忽略
+func (r *Renderer) BeginLighting() {
+	r.lighting.active = true
+	r.lighting.lights = r.lighting.lights[:0]
+}
+
+/**
+ * @brief 绘制光源精灵图，光源以加法混合照亮暗度层
+ * @param camera 相机
+ * @param sprite 光源精灵图，白色径向渐变或cookie纹理
+ * @param center 光源中心的世界坐标
+ * @param size 光源在世界中的尺寸
+ * @param color 光源颜色，A为强度
+ */
+func (r *Renderer) DrawLight(camera physics.ICamera, sprite physics.ISprite, center, size mgl32.Vec2, color emath.FColor) {
+	if !r.lighting.active {
+		return
+	}
+	texture, region := r.resourceManager.GetTextureRegion(sprite.GetTextureId())
+	if texture == nil {
+		slog.Error("light texture is nil", slog.String("textureID", sprite.GetTextureId()))
+		return
+	}
+	srcRect := r.GetSpriteSrcRect(sprite)
+	if srcRect == nil {
+		slog.Error("light sourceRect is nil", slog.String("textureID", sprite.GetTextureId()))
+		return
+	}
+	srcRect = toAtlasRect(srcRect, region)
+
+	positionScreen := camera.WorldToScreen(center.Sub(size.Mul(0.5)))
+	dstRect := sdl.FRect{
+		X: positionScreen.X(),
+		Y: positionScreen.Y(),
+		W: size.X() * camera.GetZoom(),
+		H: size.Y() * camera.GetZoom(),
+	}
+	if !r.IsInViewport(camera, dstRect) {
+		return
+	}
+	r.lighting.lights = append(r.lighting.lights, drawCommand{
+		texture:   texture,
+		srcRect:   *srcRect,
+		dstRect:   dstRect,
+		isFlipped: sprite.GetIsFlipped(),
+		color:     mulColor(sprite.GetColor(), color),
+		blendMode: sdl.BlendModeAdd,
+	})
+}
+
+/**
+ * @brief 结束收集光源，将暗度层合成到当前视口
+ * @param camera 相机，暗度层覆盖相机的整个视口
+ * @param ambient 环境光颜色，没有光源照亮的部分以此颜色调制
+ */
+func (r *Renderer) EndLighting(camera physics.ICamera, ambient emath.FColor) {
+	if !r.lighting.active {
+		return
+	}
+	r.lighting.active = false
+	viewportSize := camera.GetViewportSize()
+	target := r.lightTarget(int32(viewportSize.X()), int32(viewportSize.Y()))
+	if target == nil {
+		return
+	}
+
+	lights := r.lighting.lights
+	r.RenderToTexture(target, func() {
+		r.DrawUIFilledRect(emath.Rect{Size: viewportSize}, emath.FColor{R: ambient.R, G: ambient.G, B: ambient.B, A: 1.0})
+		r.drawCommands(lights)
+	})
+	r.lighting.lights = lights[:0]
+
+	dstRect := sdl.FRect{X: 0.0, Y: 0.0, W: viewportSize.X(), H: viewportSize.Y()}
+	if !sdl.RenderTexture(r.sdlRenderer, target, nil, &dstRect) {
+		slog.Error("render light target failed", slog.String("error", sdl.GetError()))
+	}
+}
+
+// 获取指定尺寸的暗度层，尺寸变化时重新创建
+func (r *Renderer) lightTarget(w, h int32) *sdl.Texture {
+	if w <= 0 || h <= 0 {
+		return nil
+	}
+	if r.lighting.target != nil && r.lighting.targetW == w && r.lighting.targetH == h {
+		return r.lighting.target
+	}
+	if r.lighting.target != nil {
+		sdl.DestroyTexture(r.lighting.target)
+		r.lighting.target = nil
+	}
+	target := r.CreateRenderTarget(w, h)
+	if target == nil {
+		return nil
+	}
+	// 合成时 场景颜色 = 场景颜色 * 暗度层颜色
+	if !sdl.SetTextureBlendMode(target, sdl.BlendModeMod) {
+		slog.Error("set light target blend mode failed", slog.String("error", sdl.GetError()))
+		sdl.DestroyTexture(target)
+		return nil
+	}
+	r.lighting.target = target
+	r.lighting.targetW, r.lighting.targetH = w, h
+	slog.Debug("create light target", slog.Int("w", int(w)), slog.Int("h", int(h)))
+	return target
+}
+
+// 释放光照使用的渲染目标
+func (r *Renderer) Close() {
+	if r.lighting.target != nil {
+		sdl.DestroyTexture(r.lighting.target)
+		r.lighting.target = nil
+	}
+}
@@ -2,6 +2,7 @@ package render
 
 import (
 	"log/slog"
+	"math"
 
 	"sunny_land/src/engine/physics"
 	"sunny_land/src/engine/resource"
@@ -11,12 +12,37 @@ import (
 	"github.com/go-gl/mathgl/mgl32"
 )
 
+// 圆形开口的分段数
+const irisSegments = 64
+
 // 渲染器
 type Renderer struct {
 	// SDL渲染器
 	sdlRenderer *sdl.Renderer
 	// 资源管理器
 	resourceManager *resource.ResourceManager
+	// 清屏颜色
+	clearColor emath.FColor
+	// 颜色调制，RGB为色调，A为不透明度
+	colorMod emath.FColor
+	// 是否正在收集绘制命令
+	queueing bool
+	// 绘制命令队列
+	queue []drawCommand
+	// 下一个绘制命令的提交顺序
+	seq int
+	// 当前图层与排序键，作用于之后提交的绘制命令
+	drawLayer int
+	sortKey   float32
+	// 批量绘制时复用的顶点与索引缓冲
+	vertices []sdl.Vertex
+	indices  []int32
+	// 本帧绘制的精灵图数量与绘制调用次数
+	spriteCount, drawCalls int
+	// 上一帧的统计
+	lastSpriteCount, lastDrawCalls int
+	// 光照
+	lighting lighting
 }
 
 // 确保Renderer实现了IRenderer接口
@@ -35,6 +61,10 @@ func NewRenderer(sdlRenderer *sdl.Renderer, resourceManager *resource.ResourceMa
 	renderer := &Renderer{
 		sdlRenderer:     sdlRenderer,
 		resourceManager: resourceManager,
+		clearColor:      emath.FColor{R: 0.0, G: 0.0, B: 0.0, A: 1.0},
+		colorMod:        emath.FColor{R: 1.0, G: 1.0, B: 1.0, A: 1.0},
+		queue:           make([]drawCommand, 0),
+		drawLayer:       DrawLayerDefault,
 	}
 	renderer.SetDrawColor(0, 0, 0, 255)
 
@@ -45,7 +75,7 @@ func NewRenderer(sdlRenderer *sdl.Renderer, resourceManager *resource.ResourceMa
 
 // 绘制精灵图
 func (r *Renderer) DrawSprite(camera physics.ICamera, sprite physics.ISprite, position, scale mgl32.Vec2, angle float64) {
-	texture := r.resourceManager.GetTexture(sprite.GetTextureId())
+	texture, region := r.resourceManager.GetTextureRegion(sprite.GetTextureId())
 	if texture == nil {
 		slog.Error("texture is nil", slog.String("textureID", sprite.GetTextureId()))
 		return
@@ -57,6 +87,7 @@ func (r *Renderer) DrawSprite(camera physics.ICamera, sprite physics.ISprite, po
 		slog.Error("sourceRect is nil", slog.String("textureID", sprite.GetTextureId()))
 		return
 	}
+	srcRect = toAtlasRect(srcRect, region)
 
 	// 应用相机转化
 	positionScreen := camera.WorldToScreen(position)
@@ -64,8 +95,8 @@ func (r *Renderer) DrawSprite(camera physics.ICamera, sprite physics.ISprite, po
 	dstRect := sdl.FRect{
 		X: positionScreen.X(),
 		Y: positionScreen.Y(),
-		W: srcRect.W * scale.X(),
-		H: srcRect.H * scale.Y(),
+		W: srcRect.W * scale.X() * camera.GetZoom(),
+		H: srcRect.H * scale.Y() * camera.GetZoom(),
 	}
 
 	// 视口裁剪
@@ -73,22 +104,23 @@ func (r *Renderer) DrawSprite(camera physics.ICamera, sprite physics.ISprite, po
 		return
 	}
 
-	flipMode := sdl.FlipNone
-	if sprite.GetIsFlipped() {
-		flipMode = sdl.FlipHorizontal
-	}
-	// 执行绘制，默认旋转中心为精灵的中心点
-	if !sdl.RenderTextureRotated(r.sdlRenderer, texture, srcRect, &dstRect, angle, nil, flipMode) {
-		slog.Error("render texture rotated failed", slog.String("textureID", sprite.GetTextureId()), slog.Any("srcRect", srcRect),
-			slog.Float64("angle", angle), slog.Any("flipMode", flipMode))
-	}
+	// 提交绘制，旋转中心为精灵的中心点
+	r.submit(drawCommand{
+		texture:   texture,
+		srcRect:   *srcRect,
+		dstRect:   dstRect,
+		angle:     angle,
+		isFlipped: sprite.GetIsFlipped(),
+		color:     sprite.GetColor(),
+		blendMode: sprite.GetBlendMode(),
+	})
 }
 
 // 绘制视差精灵图
 func (r *Renderer) DrawSpriteWithParallax(camera physics.ICamera, sprite physics.ISprite, position, scrollFactor, scale mgl32.Vec2, repeat emath.Vec2B) {
-	texture := r.resourceManager.GetTexture(sprite.(*Sprite).textureId)
+	texture, region := r.resourceManager.GetTextureRegion(sprite.GetTextureId())
 	if texture == nil {
-		slog.Error("texture is nil", slog.String("textureID", sprite.(*Sprite).textureId))
+		slog.Error("texture is nil", slog.String("textureID", sprite.GetTextureId()))
 		return
 	}
 
@@ -98,13 +130,14 @@ func (r *Renderer) DrawSpriteWithParallax(camera physics.ICamera, sprite physics
 		slog.Error("sourceRect is nil", slog.String("textureID", sprite.GetTextureId()))
 		return
 	}
+	srcRect = toAtlasRect(srcRect, region)
 
 	// 应用相机转化
 	positionScreen := camera.WorldToScreenWithParallax(position, scrollFactor)
 
-	// 计算缩放后的纹理尺寸
-	scaledTexW := srcRect.W * scale.X()
-	scaledTexH := srcRect.H * scale.Y()
+	// 计算缩放后的纹理尺寸，包含相机缩放
+	scaledTexW := srcRect.W * scale.X() * camera.GetZoom()
+	scaledTexH := srcRect.H * scale.Y() * camera.GetZoom()
 
 	start := mgl32.Vec2{}
 	stop := mgl32.Vec2{}
@@ -133,24 +166,20 @@ func (r *Renderer) DrawSpriteWithParallax(camera physics.ICamera, sprite physics
 	// 开始绘制
 	for y := start.Y(); y < stop.Y(); y += scaledTexH {
 		for x := start.X(); x < stop.X(); x += scaledTexW {
-			destRect := sdl.FRect{
-				X: x,
-				Y: y,
-				W: scaledTexW,
-				H: scaledTexH,
-			}
-			if !sdl.RenderTexture(r.sdlRenderer, texture, srcRect, &destRect) {
-				slog.Error("render parallax texture failed", slog.String("textureID", sprite.GetTextureId()), slog.Any("srcRect", srcRect),
-					slog.Any("destRect", destRect))
-				return
-			}
+			r.submit(drawCommand{
+				texture:   texture,
+				srcRect:   *srcRect,
+				dstRect:   sdl.FRect{X: x, Y: y, W: scaledTexW, H: scaledTexH},
+				color:     sprite.GetColor(),
+				blendMode: sprite.GetBlendMode(),
+			})
 		}
 	}
 }
 
 // 绘制用户界面精灵图
 func (r *Renderer) DrawUISprite(sprite physics.ISprite, position mgl32.Vec2, size *mgl32.Vec2) {
-	texture := r.resourceManager.GetTexture(sprite.GetTextureId())
+	texture, region := r.resourceManager.GetTextureRegion(sprite.GetTextureId())
 	if texture == nil {
 		slog.Error("texture is nil", slog.String("textureID", sprite.GetTextureId()))
 		return
@@ -162,6 +191,14 @@ func (r *Renderer) DrawUISprite(sprite physics.ISprite, position mgl32.Vec2, siz
 		slog.Error("sourceRect is nil", slog.String("textureID", sprite.GetTextureId()))
 		return
 	}
+	srcRect = toAtlasRect(srcRect, region)
+
+	// 应用颜色调制(渲染器与精灵图的颜色调制相乘)及混合模式
+	r.applyColorMod(texture, mulColor(r.colorMod, sprite.GetColor()))
+	if blendMode := sprite.GetBlendMode(); blendMode != sdl.BlendModeBlend {
+		r.setTextureBlendMode(texture, blendMode)
+		defer r.setTextureBlendMode(texture, sdl.BlendModeBlend)
+	}
 
 	// 目标矩形
 	dstRect := sdl.FRect{
@@ -187,6 +224,80 @@ func (r *Renderer) DrawUISprite(sprite physics.ISprite, position mgl32.Vec2, siz
 	}
 }
 
+/**
+ * @brief 九宫格绘制用户界面精灵图，四角保持原始尺寸，四边沿一个方向拉伸，中心双向拉伸，
+ * 因此像素风格的边框可以缩放到任意尺寸而不变形。
+ *
+ * 目标矩形小于边框之和时边框等比缩小。
+ * @param sprite 精灵图
+ * @param rect 目标矩形(屏幕坐标)
+ * @param insets 源图中的边框宽度(像素)
+ * @param borderScale 边框的绘制缩放，例如2表示边框按两倍像素绘制
+ */
+func (r *Renderer) DrawUINineSlice(sprite physics.ISprite, rect emath.Rect, insets emath.Insets, borderScale float32) {
+	texture, region := r.resourceManager.GetTextureRegion(sprite.GetTextureId())
+	if texture == nil {
+		slog.Error("texture is nil", slog.String("textureID", sprite.GetTextureId()))
+		return
+	}
+	srcRect := r.GetSpriteSrcRect(sprite)
+	if srcRect == nil {
+		slog.Error("sourceRect is nil", slog.String("textureID", sprite.GetTextureId()))
+		return
+	}
+	srcRect = toAtlasRect(srcRect, region)
+	if insets.Horizontal() >= srcRect.W || insets.Vertical() >= srcRect.H {
+		slog.Error("nine slice insets exceed source size", slog.String("textureID", sprite.GetTextureId()), slog.Any("insets", insets),
+			slog.Any("sourceRect", srcRect))
+		return
+	}
+
+	// 目标边框宽度，放不下时等比缩小
+	dst := emath.Insets{
+		Left:   insets.Left * borderScale,
+		Top:    insets.Top * borderScale,
+		Right:  insets.Right * borderScale,
+		Bottom: insets.Bottom * borderScale,
+	}
+	if dst.Horizontal() > rect.Size.X() {
+		shrink := rect.Size.X() / dst.Horizontal()
+		dst.Left, dst.Right = dst.Left*shrink, dst.Right*shrink
+	}
+	if dst.Vertical() > rect.Size.Y() {
+		shrink := rect.Size.Y() / dst.Vertical()
+		dst.Top, dst.Bottom = dst.Top*shrink, dst.Bottom*shrink
+	}
+
+	// 三列三行的分割位置
+	srcXs := [4]float32{srcRect.X, srcRect.X + insets.Left, srcRect.X + srcRect.W - insets.Right, srcRect.X + srcRect.W}
+	srcYs := [4]float32{srcRect.Y, srcRect.Y + insets.Top, srcRect.Y + srcRect.H - insets.Bottom, srcRect.Y + srcRect.H}
+	x, y, w, h := rect.Position.X(), rect.Position.Y(), rect.Size.X(), rect.Size.Y()
+	dstXs := [4]float32{x, x + dst.Left, x + w - dst.Right, x + w}
+	dstYs := [4]float32{y, y + dst.Top, y + h - dst.Bottom, y + h}
+
+	color := mulColor(r.colorMod, sprite.GetColor())
+	cmds := make([]drawCommand, 0, 9)
+	for row := range 3 {
+		for col := range 3 {
+			slice := drawCommand{
+				texture:   texture,
+				srcRect:   sdl.FRect{X: srcXs[col], Y: srcYs[row], W: srcXs[col+1] - srcXs[col], H: srcYs[row+1] - srcYs[row]},
+				dstRect:   sdl.FRect{X: dstXs[col], Y: dstYs[row], W: dstXs[col+1] - dstXs[col], H: dstYs[row+1] - dstYs[row]},
+				color:     color,
+				blendMode: sprite.GetBlendMode(),
+			}
+			// 边框为0的切片不绘制
+			if slice.srcRect.W <= 0.0 || slice.srcRect.H <= 0.0 || slice.dstRect.W <= 0.0 || slice.dstRect.H <= 0.0 {
+				continue
+			}
+			cmds = append(cmds, slice)
+		}
+	}
+	// 用户界面立即绘制，九个切片合并为一次绘制调用
+	r.spriteCount++
+	r.drawCommands(cmds)
+}
+
 // 设置绘制颜色
 func (r *Renderer) SetDrawColorFloat(rc, gc, bc, a float32) {
 	if !sdl.SetRenderDrawColorFloat(r.sdlRenderer, rc, gc, bc, a) {
@@ -199,13 +310,79 @@ func (r *Renderer) SetDrawColor(rc, gc, bc, a uint8) {
 	}
 }
 
-// 清屏
+// 设置颜色调制(RGB为色调，A为不透明度)，作用于之后所有的精灵图绘制
+func (r *Renderer) SetColorMod(color emath.FColor) {
+	r.colorMod = color
+}
+
+// 重置颜色调制为白色不透明
+func (r *Renderer) ResetColorMod() {
+	r.colorMod = emath.FColor{R: 1.0, G: 1.0, B: 1.0, A: 1.0}
+}
+
+// 获取颜色调制
+func (r *Renderer) GetColorMod() emath.FColor {
+	return r.colorMod
+}
+
+// 将颜色调制应用到纹理上，纹理是共享的，因此每次绘制前都需要设置
+func (r *Renderer) applyColorMod(texture *sdl.Texture, color emath.FColor) {
+	if !sdl.SetTextureColorModFloat(texture, color.R, color.G, color.B) {
+		slog.Error("set texture color mod failed", slog.String("error", sdl.GetError()))
+	}
+	if !sdl.SetTextureAlphaModFloat(texture, color.A) {
+		slog.Error("set texture alpha mod failed", slog.String("error", sdl.GetError()))
+	}
+}
+
+// 设置清屏颜色
+func (r *Renderer) SetClearColor(color emath.FColor) {
+	r.clearColor = color
+}
+
+// 获取清屏颜色
+func (r *Renderer) GetClearColor() emath.FColor {
+	return r.clearColor
+}
+
+// 清屏，同时开始新一帧的绘制统计
 func (r *Renderer) ClearScreen() {
+	r.resetFrameStats()
+	r.SetDrawColorFloat(r.clearColor.R, r.clearColor.G, r.clearColor.B, r.clearColor.A)
+	defer r.SetDrawColorFloat(0.0, 0.0, 0.0, 1.0)
 	if !sdl.RenderClear(r.sdlRenderer) {
 		slog.Error("render clear failed")
 	}
 }
 
+/**
+ * @brief 开始绘制相机视口，之后的绘制坐标相对于视口左上角，超出视口的部分被裁剪
+ * @param camera 相机
+ */
+func (r *Renderer) BeginViewport(camera *Camera) {
+	rect := camera.GetViewportRect()
+	viewport := sdl.Rect{
+		X: int32(rect.Position.X()),
+		Y: int32(rect.Position.Y()),
+		W: int32(rect.Size.X()),
+		H: int32(rect.Size.Y()),
+	}
+	if !sdl.SetRenderViewport(r.sdlRenderer, &viewport) {
+		slog.Error("set render viewport failed", slog.Any("viewport", viewport), slog.String("error", sdl.GetError()))
+		return
+	}
+	if color := camera.GetBackgroundColor(); color != nil {
+		r.DrawUIFilledRect(emath.Rect{Size: rect.Size}, *color)
+	}
+}
+
+// 结束绘制相机视口，恢复为整个屏幕
+func (r *Renderer) EndViewport() {
+	if !sdl.SetRenderViewport(r.sdlRenderer, nil) {
+		slog.Error("reset render viewport failed", slog.String("error", sdl.GetError()))
+	}
+}
+
 // 渲染
 func (r *Renderer) Present() {
 	// 交换缓冲区，将渲染结果显示到屏幕上
@@ -237,6 +414,19 @@ func (r *Renderer) GetSpriteSrcRect(sprite physics.ISprite) *sdl.FRect {
 	}
 }
 
+// 将图片坐标系下的源矩形换算为图集坐标系，region为nil表示图片是单独的纹理
+func toAtlasRect(srcRect, region *sdl.FRect) *sdl.FRect {
+	if region == nil {
+		return srcRect
+	}
+	return &sdl.FRect{
+		X: region.X + srcRect.X,
+		Y: region.Y + srcRect.Y,
+		W: srcRect.W,
+		H: srcRect.H,
+	}
+}
+
 // 是否在视口内
 func (r *Renderer) IsInViewport(camera physics.ICamera, rect sdl.FRect) bool {
 	viewportSize := camera.GetViewportSize()
@@ -263,3 +453,176 @@ func (r *Renderer) DrawUIFilledRect(rect emath.Rect, color emath.FColor) {
 	}
 	r.SetDrawColorFloat(0.0, 0.0, 0.0, 1.0)
 }
+
+/**
+ * @brief 绘制矩形边框，边框向矩形内侧绘制
+ *
+ * @param rect 矩形区域
+ * @param thickness 边框宽度
+ * @param color 边框颜色
+ */
+func (r *Renderer) DrawUIRect(rect emath.Rect, thickness float32, color emath.FColor) {
+	thickness = min(thickness, rect.Size.X()*0.5, rect.Size.Y()*0.5)
+	if thickness <= 0.0 {
+		return
+	}
+	x, y, w, h := rect.Position.X(), rect.Position.Y(), rect.Size.X(), rect.Size.Y()
+	// 上下两条横边覆盖四角，左右两条竖边夹在中间，避免半透明时四角重叠
+	r.DrawUIFilledRect(emath.Rect{Position: mgl32.Vec2{x, y}, Size: mgl32.Vec2{w, thickness}}, color)
+	r.DrawUIFilledRect(emath.Rect{Position: mgl32.Vec2{x, y + h - thickness}, Size: mgl32.Vec2{w, thickness}}, color)
+	r.DrawUIFilledRect(emath.Rect{Position: mgl32.Vec2{x, y + thickness}, Size: mgl32.Vec2{thickness, h - thickness*2.0}}, color)
+	r.DrawUIFilledRect(emath.Rect{Position: mgl32.Vec2{x + w - thickness, y + thickness}, Size: mgl32.Vec2{thickness, h - thickness*2.0}}, color)
+}
+
+/**
+ * @brief 绘制带圆形开口的填充矩形，开口外为填充颜色，例如圆形转场
+ *
+ * 开口外的部分由圆环三角形组成，圆环外径覆盖整个矩形，超出矩形的部分被裁剪。
+ * @param rect 矩形区域
+ * @param center 开口中心
+ * @param radius 开口半径，不大于0时填充整个矩形
+ * @param color 填充颜色
+ */
+func (r *Renderer) DrawUIIris(rect emath.Rect, center mgl32.Vec2, radius float32, color emath.FColor) {
+	if radius <= 0.0 {
+		r.DrawUIFilledRect(rect, color)
+		return
+	}
+	// 外径为开口中心到矩形最远角的距离
+	outer := float32(0.0)
+	for _, corner := range []mgl32.Vec2{
+		rect.Position,
+		rect.Position.Add(mgl32.Vec2{rect.Size.X(), 0.0}),
+		rect.Position.Add(mgl32.Vec2{0.0, rect.Size.Y()}),
+		rect.Position.Add(rect.Size),
+	} {
+		outer = max(outer, corner.Sub(center).Len())
+	}
+	if radius >= outer {
+		return
+	}
+	outer += 1.0
+
+	clipRect := sdl.Rect{X: int32(rect.Position.X()), Y: int32(rect.Position.Y()), W: int32(rect.Size.X()), H: int32(rect.Size.Y())}
+	if !sdl.SetRenderClipRect(r.sdlRenderer, &clipRect) {
+		slog.Error("set render clip rect failed", slog.String("error", sdl.GetError()))
+	}
+	defer sdl.SetRenderClipRect(r.sdlRenderer, nil)
+
+	vertexColor := sdl.FColor{R: color.R, G: color.G, B: color.B, A: color.A}
+	r.vertices = r.vertices[:0]
+	r.indices = r.indices[:0]
+	for i := 0; i <= irisSegments; i++ {
+		sin, cos := math.Sincos(2.0 * math.Pi * float64(i) / irisSegments)
+		dir := mgl32.Vec2{float32(cos), float32(sin)}
+		inner, outerPoint := center.Add(dir.Mul(radius)), center.Add(dir.Mul(outer))
+		r.vertices = append(r.vertices,
+			sdl.Vertex{Position: sdl.FPoint{X: inner.X(), Y: inner.Y()}, Color: vertexColor},
+			sdl.Vertex{Position: sdl.FPoint{X: outerPoint.X(), Y: outerPoint.Y()}, Color: vertexColor},
+		)
+		if i > 0 {
+			base := int32(i-1) * 2
+			r.indices = append(r.indices, base, base+1, base+3, base, base+3, base+2)
+		}
+	}
+	if !sdl.RenderGeometry(r.sdlRenderer, nil, r.vertices, r.indices) {
+		slog.Error("render iris geometry failed", slog.String("error", sdl.GetError()))
+	}
+	r.drawCalls++
+}
+
+/**
+ * @brief 绘制整张纹理，例如预先绘制好的渲染目标
+ *
+ * 纹理内容应为预乘alpha：透明的渲染目标以BlendModeBlend绘制后，颜色已经乘过alpha，
+ * 因此以BlendModeBlendPremultiplied合成，避免alpha被应用两次使半透明的部分变暗。
+ * @param camera 相机
+ * @param texture 纹理
+ * @param position 世界坐标(左上角)
+ * @param size 世界中的尺寸
+ */
+func (r *Renderer) DrawTexture(camera physics.ICamera, texture *sdl.Texture, position, size mgl32.Vec2) {
+	if texture == nil {
+		slog.Error("texture is nil")
+		return
+	}
+	var w, h float32
+	if !sdl.GetTextureSize(texture, &w, &h) {
+		slog.Error("get texture size failed", slog.String("error", sdl.GetError()))
+		return
+	}
+
+	positionScreen := camera.WorldToScreen(position)
+	dstRect := sdl.FRect{X: positionScreen.X(), Y: positionScreen.Y(), W: size.X() * camera.GetZoom(), H: size.Y() * camera.GetZoom()}
+	if !r.IsInViewport(camera, dstRect) {
+		return
+	}
+	r.submit(drawCommand{
+		texture:   texture,
+		srcRect:   sdl.FRect{X: 0.0, Y: 0.0, W: w, H: h},
+		dstRect:   dstRect,
+		color:     emath.FColor{R: 1.0, G: 1.0, B: 1.0, A: 1.0},
+		blendMode: sdl.BlendModeBlendPremultiplied,
+	})
+}
+
+// 创建透明的渲染目标纹理，使用最邻近插值
+func (r *Renderer) CreateRenderTarget(w, h int32) *sdl.Texture {
+	texture := sdl.CreateTexture(r.sdlRenderer, sdl.PixelFormatRGBA32, sdl.TextureAccessTarget, w, h)
+	if texture == nil {
+		slog.Error("create render target failed", slog.Int("w", int(w)), slog.Int("h", int(h)), slog.String("error", sdl.GetError()))
+		return nil
+	}
+	if !sdl.SetTextureBlendMode(texture, sdl.BlendModeBlend) {
+		slog.Warn("set render target blend mode failed", slog.String("error", sdl.GetError()))
+	}
+	if !sdl.SetTextureScaleMode(texture, sdl.ScaleModeNearest) {
+		slog.Warn("set render target scale mode failed", slog.String("error", sdl.GetError()))
+	}
+	return texture
+}
+
+/**
+ * @brief 将绘制重定向到渲染目标纹理，目标先被清除为透明
+ *
+ * 回调中的精灵图绘制立即执行(不进入绘制队列)，颜色调制与绘制顺序被重置，
+ * 回调结束后恢复之前的渲染目标及状态。
+ * @param target 渲染目标纹理
+ * @param draw 绘制回调
+ * @return bool 是否成功
+ */
+func (r *Renderer) RenderToTexture(target *sdl.Texture, draw func()) bool {
+	prevTarget := sdl.GetRenderTarget(r.sdlRenderer)
+	if !sdl.SetRenderTarget(r.sdlRenderer, target) {
+		slog.Error("set render target failed", slog.String("error", sdl.GetError()))
+		return false
+	}
+
+	// 保存状态
+	queueing, colorMod := r.queueing, r.colorMod
+	drawLayer, sortKey := r.drawLayer, r.sortKey
+	r.queueing = false
+	r.ResetColorMod()
+	r.ResetDrawOrder()
+
+	r.SetDrawColorFloat(0.0, 0.0, 0.0, 0.0)
+	if !sdl.RenderClear(r.sdlRenderer) {
+		slog.Error("render clear target failed", slog.String("error", sdl.GetError()))
+	}
+	r.SetDrawColorFloat(0.0, 0.0, 0.0, 1.0)
+	draw()
+
+	// 恢复状态
+	r.queueing, r.colorMod = queueing, colorMod
+	r.drawLayer, r.sortKey = drawLayer, sortKey
+	if !sdl.SetRenderTarget(r.sdlRenderer, prevTarget) {
+		slog.Error("restore render target failed", slog.String("error", sdl.GetError()))
+		return false
+	}
+	return true
+}
+
+// 获取纹理重新加载的次数，缓存了纹理内容的渲染目标据此判断是否需要重绘
+func (r *Renderer) GetTextureGeneration() uint64 {
+	return r.resourceManager.GetTextureGeneration()
+}
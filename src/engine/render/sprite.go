@@ -4,6 +4,7 @@ import (
 	"log/slog"
 
 	"sunny_land/src/engine/physics"
+	emath "sunny_land/src/engine/utils/math"
 
 	"github.com/SunshineZzzz/purego-sdl3/sdl"
 )
@@ -16,6 +17,10 @@ type Sprite struct {
 	sourceRect *sdl.FRect
 	// 是否水平反转
 	isFlipped bool
+	// 颜色调制，RGB为色调，A为不透明度
+	color emath.FColor
+	// 混合模式
+	blendMode sdl.BlendMode
 }
 
 // 确保Sprite实现了ISprite接口
@@ -28,6 +33,8 @@ func NewSprite(textureId string, sourceRect *sdl.FRect, isFlipped bool) *Sprite
 		textureId:  textureId,
 		sourceRect: sourceRect,
 		isFlipped:  isFlipped,
+		color:      emath.FColor{R: 1.0, G: 1.0, B: 1.0, A: 1.0},
+		blendMode:  sdl.BlendModeBlend,
 	}
 }
 
@@ -60,3 +67,33 @@ func (s *Sprite) GetIsFlipped() bool {
 func (s *Sprite) SetIsFlipped(isFlipped bool) {
 	s.isFlipped = isFlipped
 }
+
+// 获取颜色调制
+func (s *Sprite) GetColor() emath.FColor {
+	return s.color
+}
+
+// 设置颜色调制，RGB为色调，A为不透明度，与渲染器的颜色调制相乘
+func (s *Sprite) SetColor(color emath.FColor) {
+	s.color = color
+}
+
+// 获取不透明度
+func (s *Sprite) GetAlpha() float32 {
+	return s.color.A
+}
+
+// 设置不透明度，0.0完全透明，1.0不透明
+func (s *Sprite) SetAlpha(alpha float32) {
+	s.color.A = alpha
+}
+
+// 获取混合模式
+func (s *Sprite) GetBlendMode() sdl.BlendMode {
+	return s.blendMode
+}
+
+// 设置混合模式，例如sdl.BlendModeAdd用于发光效果
+func (s *Sprite) SetBlendMode(blendMode sdl.BlendMode) {
+	s.blendMode = blendMode
+}
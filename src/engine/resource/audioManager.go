@@ -3,8 +3,8 @@ package resource
 import (
 	"fmt"
 	"log/slog"
-	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"sync"
 	"unsafe"
@@ -55,6 +55,8 @@ type IAudio interface {
 	GetVolume() float32
 	// 是否正在播放
 	IsPlaying() bool
+	// 获取解码后的音频数据大小(字节)
+	GetDataSize() int
 }
 
 // 全局音频句柄管理
@@ -179,6 +181,14 @@ func (am *audioManager) loadSound(filePath string) *[]IAudio {
 	return am.sounds[filePath]
 }
 
+// 将预加载解码的音频放入缓存(音效或音乐)，已存在时加入音频池
+func (am *audioManager) addAudio(cache map[string]*[]IAudio, filePath string, audio IAudio) {
+	if _, ok := cache[filePath]; !ok {
+		cache[filePath] = new([]IAudio)
+	}
+	*cache[filePath] = append(*cache[filePath], audio)
+}
+
 // 获取音效
 func (am *audioManager) GetSound(filePath string) *[]IAudio {
 	sound, ok := am.sounds[filePath]
@@ -239,6 +249,37 @@ func (am *audioManager) UnloadMusic(filePath string) {
 	delete(am.musics, filePath)
 }
 
+/**
+ * @brief 重新加载已缓存的音效与音乐，在原切片中替换，持有切片指针的使用者会自动使用新音频
+ * 旧音频会被关闭，正在播放的音乐需要由使用者重新播放
+ * @param filePath 音频文件路径
+ * @return bool 是否有音频被重新加载
+ */
+func (am *audioManager) reloadAudio(filePath string) bool {
+	reloaded := false
+	reload := func(cache map[string]*[]IAudio, audioType AudioType) {
+		for key, audios := range cache {
+			if !samePath(key, filePath) {
+				continue
+			}
+			audio, err := newAudio(key, audioType)
+			if err != nil {
+				slog.Error("reload audio error", slog.String("path", key), slog.String("error", err.Error()))
+				continue
+			}
+			for _, old := range *audios {
+				old.Close()
+			}
+			*audios = []IAudio{audio}
+			reloaded = true
+			slog.Info("audio reloaded", slog.String("path", key))
+		}
+	}
+	reload(am.sounds, AudioTypeEffect)
+	reload(am.musics, AudioTypeMusic)
+	return reloaded
+}
+
 // 设置音效音量
 func (am *audioManager) SetSoundVolume(volume float32) {
 	if volume < 0.0 {
@@ -299,7 +340,7 @@ type oggAudio struct {
 var _ IAudio = (*oggAudio)(nil)
 
 func newOggAudio(audioFilePath string, audioType AudioType) (*oggAudio, error) {
-	file, err := os.Open(audioFilePath)
+	file, err := defaultVFS.Open(audioFilePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open audio file, %v, %v", audioFilePath, err)
 	}
@@ -544,6 +585,11 @@ func (o *oggAudio) IsPlaying() bool {
 	return o.isPlaying
 }
 
+// 获取解码后的音频数据大小(字节)
+func (o *oggAudio) GetDataSize() int {
+	return len(o.audioData)
+}
+
 // wav格式音频
 type wavAudio struct {
 	// 锁
@@ -572,8 +618,12 @@ type wavAudio struct {
 
 // 创建wav音频
 func newWavAudio(soundFilePath string, audioType AudioType) (*wavAudio, error) {
-	// 打开文件IO流
-	ioStream := sdl.IOFromFile(soundFilePath, "rb")
+	// 通过虚拟文件系统读取文件，再打开内存IO流
+	data, err := defaultVFS.ReadFile(soundFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read WAV file, %v, %v", soundFilePath, err)
+	}
+	ioStream := sdl.IOFromConstMem(data)
 	if ioStream == nil {
 		return nil, fmt.Errorf("failed to open WAV file: %s", sdl.GetError())
 	}
@@ -586,6 +636,7 @@ func newWavAudio(soundFilePath string, audioType AudioType) (*wavAudio, error) {
 	spec := &sdl.AudioSpec{}
 	// 加载WAV数据
 	success := sdl.LoadWAVIO(ioStream, true, spec, &audioBuf, &audioLen)
+	runtime.KeepAlive(data)
 	if !success {
 		return nil, fmt.Errorf("failed to load WAV data: %s", sdl.GetError())
 	}
@@ -813,6 +864,11 @@ func (w *wavAudio) IsPlaying() bool {
 	return w.isPlaying
 }
 
+// 获取解码后的音频数据大小(字节)
+func (w *wavAudio) GetDataSize() int {
+	return w.audioLen
+}
+
 // mp3格式音频
 type mp3Audio struct {
 	// 锁
@@ -841,7 +897,7 @@ type mp3Audio struct {
 var _ IAudio = (*mp3Audio)(nil)
 
 func newMp3Audio(audioFilePath string, audioType AudioType) (*mp3Audio, error) {
-	file, err := os.Open(audioFilePath)
+	file, err := defaultVFS.Open(audioFilePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open audio file, %v, %v", audioFilePath, err)
 	}
@@ -1088,3 +1144,8 @@ func (o *mp3Audio) IsPlaying() bool {
 
 	return o.isPlaying
 }
+
+// 获取解码后的音频数据大小(字节)
+func (o *mp3Audio) GetDataSize() int {
+	return len(o.audioData)
+}
@@ -0,0 +1,88 @@
+package resource
+
+import (
+	"io/fs"
+	"log/slog"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// 文件状态，修改时间与大小任一变化即视为文件被修改
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+/**
+ * @brief 轮询式文件监视器，用于开发时的资源热重载。
+ *
+ * 在主线程中每隔一定时间遍历一次目录，比较文件的修改时间与大小，
+ * 返回新增或被修改的文件路径，删除的文件不报告。
+ */
+type FileWatcher struct {
+	// 监视的根目录
+	root string
+	// 轮询间隔(秒)
+	interval float64
+	// 距离上次轮询经过的时间(秒)
+	elapsed float64
+	// 文件状态，路径 -> 状态
+	stamps map[string]fileStamp
+}
+
+// 创建文件监视器，创建时记录一次当前状态
+func NewFileWatcher(root string, interval float64) *FileWatcher {
+	fw := &FileWatcher{
+		root:     root,
+		interval: interval,
+		stamps:   make(map[string]fileStamp),
+	}
+	fw.scan()
+	slog.Info("file watcher started", slog.String("root", root), slog.Float64("interval", interval))
+	return fw
+}
+
+/**
+ * @brief 更新监视器，到达轮询间隔时检查文件变化
+ * @param dt 帧间隔时间(秒)
+ * @return []string 新增或被修改的文件路径，没有变化返回nil
+ */
+func (fw *FileWatcher) Update(dt float64) []string {
+	fw.elapsed += dt
+	if fw.elapsed < fw.interval {
+		return nil
+	}
+	fw.elapsed = 0.0
+	return fw.scan()
+}
+
+// 遍历目录，更新文件状态并返回变化的文件，首次扫描不报告
+func (fw *FileWatcher) scan() []string {
+	firstScan := len(fw.stamps) == 0
+	var changed []string
+	err := filepath.WalkDir(fw.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// 编辑器保存文件时可能短暂不存在，忽略即可
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		stamp := fileStamp{modTime: info.ModTime(), size: info.Size()}
+		if old, ok := fw.stamps[path]; !firstScan && (!ok || old != stamp) {
+			changed = append(changed, path)
+		}
+		fw.stamps[path] = stamp
+		return nil
+	})
+	if err != nil {
+		slog.Error("file watcher walk failed", slog.String("root", fw.root), slog.String("error", err.Error()))
+	}
+	sort.Strings(changed)
+	return changed
+}
@@ -28,6 +28,10 @@ func (fk fontKey) hash() uint64 {
 // 字体管理器
 type fontManager struct {
 	fonts map[uint64]*ttf.Font
+	// 字体键，哈希 -> 键，用于按路径查找已加载的字体
+	keys map[uint64]fontKey
+	// 字体文件数据，哈希 -> 数据，SDL_ttf会在使用字体时按需读取，字体关闭前必须保留
+	datas map[uint64][]byte
 }
 
 // 创建字体管理器
@@ -40,6 +44,8 @@ func NewFontManager() *fontManager {
 	slog.Debug("font manager init")
 	return &fontManager{
 		fonts: make(map[uint64]*ttf.Font),
+		keys:  make(map[uint64]fontKey),
+		datas: make(map[uint64][]byte),
 	}
 }
 
@@ -49,6 +55,8 @@ func (fm *fontManager) Clear() {
 		ttf.CloseFont(font)
 	}
 	fm.fonts = make(map[uint64]*ttf.Font)
+	fm.keys = make(map[uint64]fontKey)
+	fm.datas = make(map[uint64][]byte)
 
 	ttf.Quit()
 	slog.Debug("font manager clear")
@@ -70,17 +78,54 @@ func (fm *fontManager) loadFont(path string, size int) *ttf.Font {
 		return font
 	}
 
-	font = ttf.OpenFont(path, float32(size))
+	font, data := fm.openFontFile(path, size)
 	if font == nil {
 		slog.Error("open font error", slog.String("path", path), slog.Int("size", size), slog.String("error", sdl.GetError()))
 		return nil
 	}
 
 	fm.fonts[fontKey.hash()] = font
+	fm.keys[fontKey.hash()] = fontKey
+	fm.datas[fontKey.hash()] = data
 	slog.Debug("load font size", slog.String("path", path), slog.Int("size", size))
 	return font
 }
 
+// 通过虚拟文件系统读取字体文件并打开字体，返回字体与需要保留的文件数据
+func (fm *fontManager) openFontFile(path string, size int) (*ttf.Font, []byte) {
+	data, err := defaultVFS.ReadFile(path)
+	if err != nil {
+		slog.Error("read font file error", slog.String("path", path), slog.String("error", err.Error()))
+		return nil, nil
+	}
+	font := ttf.OpenFontIO(sdl.IOFromConstMem(data), true, float32(size))
+	if font == nil {
+		return nil, nil
+	}
+	return font, data
+}
+
+// 使用预加载读取的文件数据打开字体并放入缓存
+func (fm *fontManager) addFontData(path string, size int, data []byte) bool {
+	fontKey := fontKey{
+		path: path,
+		size: size,
+	}
+	if _, ok := fm.fonts[fontKey.hash()]; ok {
+		return true
+	}
+
+	font := ttf.OpenFontIO(sdl.IOFromConstMem(data), true, float32(size))
+	if font == nil {
+		slog.Error("open font error", slog.String("path", path), slog.Int("size", size), slog.String("error", sdl.GetError()))
+		return false
+	}
+	fm.fonts[fontKey.hash()] = font
+	fm.keys[fontKey.hash()] = fontKey
+	fm.datas[fontKey.hash()] = data
+	return true
+}
+
 // 获取字体
 func (fm *fontManager) GetFont(path string, size int) *ttf.Font {
 	fontKey := fontKey{
@@ -108,5 +153,32 @@ func (fm *fontManager) UnloadFont(path string, size int) {
 	}
 	ttf.CloseFont(font)
 	delete(fm.fonts, fontKey.hash())
+	delete(fm.keys, fontKey.hash())
+	delete(fm.datas, fontKey.hash())
 	slog.Debug("unload font size", slog.String("path", path), slog.Int("size", size))
 }
+
+/**
+ * @brief 重新加载已缓存的字体(所有字号)，文本渲染时通过字体Id获取字体，因此会自动使用新字体
+ * @param path 字体文件路径
+ * @return bool 是否有字体被重新加载
+ */
+func (fm *fontManager) reloadFont(path string) bool {
+	reloaded := false
+	for hash, key := range fm.keys {
+		if !samePath(key.path, path) {
+			continue
+		}
+		font, data := fm.openFontFile(key.path, key.size)
+		if font == nil {
+			slog.Error("reload font error", slog.String("path", key.path), slog.Int("size", key.size), slog.String("error", sdl.GetError()))
+			continue
+		}
+		ttf.CloseFont(fm.fonts[hash])
+		fm.fonts[hash] = font
+		fm.datas[hash] = data
+		reloaded = true
+		slog.Info("font reloaded", slog.String("path", key.path), slog.Int("size", key.size))
+	}
+	return reloaded
+}
@@ -0,0 +1,279 @@
+package resource
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"runtime"
+
+	"github.com/SunshineZzzz/purego-sdl3/img"
+	"github.com/SunshineZzzz/purego-sdl3/sdl"
+)
+
+// 预加载清单中的字体
+type ManifestFont struct {
+	Path string `json:"path"`
+	Size int    `json:"size"`
+}
+
+/**
+ * @brief 预加载清单，对应json文件：
+ * {
+ *   "textures": ["assets/textures/Layers/back.png"],
+ *   "fonts":    [{"path": "assets/fonts/VonwaonBitmap-16px.ttf", "size": 16}],
+ *   "sounds":   ["assets/audio/poka01.mp3"],
+ *   "music":    ["assets/audio/hurry_up_and_run.ogg"]
+ * }
+ */
+type Manifest struct {
+	Textures []string       `json:"textures"`
+	Fonts    []ManifestFont `json:"fonts"`
+	Sounds   []string       `json:"sounds"`
+	Music    []string       `json:"music"`
+}
+
+// 通过虚拟文件系统加载预加载清单
+func LoadManifest(path string) *Manifest {
+	data, err := defaultVFS.ReadFile(path)
+	if err != nil {
+		slog.Error("read manifest failed", slog.String("path", path), slog.String("error", err.Error()))
+		return nil
+	}
+	manifest := &Manifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		slog.Error("unmarshal manifest failed", slog.String("path", path), slog.String("error", err.Error()))
+		return nil
+	}
+	return manifest
+}
+
+// 预加载资源类型
+type preloadKind int
+
+const (
+	preloadKindTexture preloadKind = iota
+	preloadKindFont
+	preloadKindSound
+	preloadKindMusic
+)
+
+// 预加载条目
+type preloadItem struct {
+	kind preloadKind
+	path string
+	// 字体大小，仅字体使用
+	size int
+}
+
+// 获取条目对应的资源键
+func (item preloadItem) key() resourceKey {
+	switch item.kind {
+	case preloadKindTexture:
+		return resourceKey{kind: resourceKindTexture, path: item.path}
+	case preloadKindFont:
+		return resourceKey{kind: resourceKindFont, path: item.path, size: item.size}
+	case preloadKindSound:
+		return resourceKey{kind: resourceKindSound, path: item.path}
+	}
+	return resourceKey{kind: resourceKindMusic, path: item.path}
+}
+
+// 工作协程的处理结果
+type preloadResult struct {
+	item preloadItem
+	// 解码后的图片，纹理使用，需要在主线程上传为纹理
+	surface *sdl.Surface
+	// 文件数据，字体使用
+	data []byte
+	// 解码后的音频，音效和音乐使用
+	audio IAudio
+	// 错误
+	err error
+}
+
+/**
+ * @brief 异步预加载任务。
+ *
+ * 文件读取与解码(图片、音频)在工作协程中完成，纹理上传等需要渲染器的操作
+ * 由主线程每帧调用Update完成，加载结果进入资源管理器的缓存，之后的Get*直接命中。
+ */
+type PreloadTask struct {
+	// 资源管理器
+	resourceManager *ResourceManager
+	// 加载的资源记录到该作用域，nil表示全局作用域
+	scope *ResourceScope
+	// 总数量
+	total int
+	// 已完成数量(包含失败)
+	completed int
+	// 失败数量
+	failed int
+	// 工作协程的处理结果
+	results chan preloadResult
+}
+
+/**
+ * @brief 按清单异步预加载资源，已缓存的资源直接计为完成
+ * @param manifest 预加载清单
+ * @param workers 工作协程数量，<=0时使用CPU核数(最多4个)
+ * @param scope 资源记录到该作用域(一般为即将进入的场景)，nil表示全局作用域
+ * @return *PreloadTask 预加载任务，需要每帧在主线程调用Update
+ */
+func (rm *ResourceManager) Preload(manifest *Manifest, workers int, scope *ResourceScope) *PreloadTask {
+	items := make([]preloadItem, 0)
+	// 已缓存的资源不需要加载，只记录引用
+	addItem := func(item preloadItem, cached bool) {
+		if cached {
+			rm.acquireInScope(scope, item.key())
+			return
+		}
+		items = append(items, item)
+	}
+	if manifest != nil {
+		for _, path := range manifest.Textures {
+			addItem(preloadItem{kind: preloadKindTexture, path: path}, rm.textureManager.has(path))
+		}
+		for _, font := range manifest.Fonts {
+			_, ok := rm.fontManager.fonts[fontKey{path: font.Path, size: font.Size}.hash()]
+			addItem(preloadItem{kind: preloadKindFont, path: font.Path, size: font.Size}, ok)
+		}
+		for _, path := range manifest.Sounds {
+			_, ok := rm.audioManager.sounds[path]
+			addItem(preloadItem{kind: preloadKindSound, path: path}, ok)
+		}
+		for _, path := range manifest.Music {
+			_, ok := rm.audioManager.musics[path]
+			addItem(preloadItem{kind: preloadKindMusic, path: path}, ok)
+		}
+	}
+
+	task := &PreloadTask{
+		resourceManager: rm,
+		scope:           scope,
+		total:           len(items),
+		// 缓冲区容纳全部结果，工作协程不会因为主线程未及时处理而阻塞
+		results: make(chan preloadResult, len(items)),
+	}
+	if len(items) == 0 {
+		return task
+	}
+
+	if workers <= 0 {
+		workers = min(runtime.NumCPU(), 4)
+	}
+	queue := make(chan preloadItem, len(items))
+	for _, item := range items {
+		queue <- item
+	}
+	close(queue)
+	for i := 0; i < workers; i++ {
+		go func() {
+			for item := range queue {
+				task.results <- decodePreloadItem(item)
+			}
+		}()
+	}
+	slog.Info("preload started", slog.Int("total", task.total), slog.Int("workers", workers))
+	return task
+}
+
+// 在工作协程中读取并解码资源，不能使用渲染器
+func decodePreloadItem(item preloadItem) preloadResult {
+	result := preloadResult{item: item}
+	switch item.kind {
+	case preloadKindTexture:
+		data, err := defaultVFS.ReadFile(item.path)
+		if err != nil {
+			result.err = err
+			return result
+		}
+		result.surface = img.LoadIO(sdl.IOFromConstMem(data), true)
+		runtime.KeepAlive(data)
+		if result.surface == nil {
+			result.err = fmt.Errorf("decode image failed, %s", sdl.GetError())
+		}
+	case preloadKindFont:
+		result.data, result.err = defaultVFS.ReadFile(item.path)
+	case preloadKindSound:
+		result.audio, result.err = newAudio(item.path, AudioTypeEffect)
+	case preloadKindMusic:
+		result.audio, result.err = newAudio(item.path, AudioTypeMusic)
+	}
+	return result
+}
+
+/**
+ * @brief 在主线程处理已解码的资源，上传纹理并放入缓存，每帧调用
+ * @return bool 是否全部完成
+ */
+func (pt *PreloadTask) Update() bool {
+	for !pt.IsDone() {
+		select {
+		case result := <-pt.results:
+			pt.finish(result)
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// 将一个处理结果放入资源管理器的缓存
+func (pt *PreloadTask) finish(result preloadResult) {
+	pt.completed++
+	item := result.item
+	if result.err != nil {
+		pt.failed++
+		slog.Error("preload failed", slog.String("path", item.path), slog.String("error", result.err.Error()))
+		return
+	}
+
+	rm := pt.resourceManager
+	ok := true
+	switch item.kind {
+	case preloadKindTexture:
+		ok = rm.textureManager.addSurface(item.path, result.surface)
+	case preloadKindFont:
+		ok = rm.fontManager.addFontData(item.path, item.size, result.data)
+	case preloadKindSound:
+		rm.audioManager.addAudio(rm.audioManager.sounds, item.path, result.audio)
+	case preloadKindMusic:
+		rm.audioManager.addAudio(rm.audioManager.musics, item.path, result.audio)
+	}
+	if ok {
+		rm.acquireInScope(pt.scope, item.key())
+	} else {
+		pt.failed++
+	}
+	if pt.IsDone() {
+		slog.Info("preload finished", slog.Int("total", pt.total), slog.Int("failed", pt.failed))
+	}
+}
+
+// 是否全部完成
+func (pt *PreloadTask) IsDone() bool {
+	return pt.completed >= pt.total
+}
+
+// 获取进度，范围0.0-1.0
+func (pt *PreloadTask) GetProgress() float32 {
+	if pt.total == 0 {
+		return 1.0
+	}
+	return float32(pt.completed) / float32(pt.total)
+}
+
+// 获取已完成数量(包含失败)
+func (pt *PreloadTask) GetCompleted() int {
+	return pt.completed
+}
+
+// 获取总数量
+func (pt *PreloadTask) GetTotal() int {
+	return pt.total
+}
+
+// 获取失败数量
+func (pt *PreloadTask) GetFailed() int {
+	return pt.failed
+}
@@ -2,6 +2,9 @@ package resource
 
 import (
 	"log/slog"
+	"path/filepath"
+
+	"sunny_land/src/engine/vfs"
 
 	"github.com/SunshineZzzz/purego-sdl3/sdl"
 	"github.com/SunshineZzzz/purego-sdl3/ttf"
@@ -16,16 +19,27 @@ type ResourceManager struct {
 	textureManager *textureManager
 	// 音频管理器
 	audioManager *audioManager
+	// 全局作用域，没有场景激活时使用，永不释放
+	globalScope *ResourceScope
+	// 当前作用域，nil表示全局作用域
+	currentScope *ResourceScope
+	// 资源引用计数，即使用该资源的作用域数量
+	refCounts map[resourceKey]int
+	// 纹理重新加载的次数，缓存了纹理内容的对象(例如瓦片层区块)据此判断是否需要重绘
+	textureGeneration uint64
 }
 
 // 创建资源管理器
 func NewResourceManager(renderer *sdl.Renderer) *ResourceManager {
 	slog.Debug("resource manager init")
-	return &ResourceManager{
+	rm := &ResourceManager{
 		fontManager:    NewFontManager(),
 		textureManager: NewTextureManager(renderer),
 		audioManager:   NewAudioManager(),
+		refCounts:      make(map[resourceKey]int),
 	}
+	rm.globalScope = rm.NewScope("global")
+	return rm
 }
 
 // 清理资源管理器
@@ -33,62 +47,131 @@ func (rm *ResourceManager) Clear() {
 	rm.fontManager.Clear()
 	rm.textureManager.Clear()
 	rm.audioManager.Clear()
+	rm.refCounts = make(map[resourceKey]int)
 
 	slog.Debug("resource manager clear")
 }
 
-// 获取纹理
+// 获取虚拟文件系统，所有资源文件都通过它读取
+func (rm *ResourceManager) GetVFS() *vfs.VFS {
+	return defaultVFS
+}
+
+// 通过虚拟文件系统读取文件
+func (rm *ResourceManager) ReadFile(path string) ([]byte, error) {
+	return defaultVFS.ReadFile(path)
+}
+
+// 获取纹理，图片被打包到图集时返回图集纹理，绘制时需要通过GetTextureRegion换算源矩形
 func (rm *ResourceManager) GetTexture(path string) *sdl.Texture {
-	return rm.textureManager.GetTexture(path)
+	texture := rm.textureManager.GetTexture(path)
+	if texture != nil {
+		rm.acquire(resourceKey{kind: resourceKindTexture, path: path})
+	}
+	return texture
+}
+
+/**
+ * @brief 获取纹理及图片在纹理中的区域，小图片会被打包到图集中
+ * @param path 纹理文件路径
+ * @return *sdl.Texture 纹理，图片被打包时为图集纹理
+ * @return *sdl.FRect 图片在图集中的区域，单独的纹理为nil
+ */
+func (rm *ResourceManager) GetTextureRegion(path string) (*sdl.Texture, *sdl.FRect) {
+	texture, region := rm.textureManager.GetTextureRegion(path)
+	if texture != nil {
+		rm.acquire(resourceKey{kind: resourceKindTexture, path: path})
+	}
+	return texture, region
+}
+
+// 设置是否将小图片打包到纹理图集，只影响之后加载的纹理
+func (rm *ResourceManager) SetTextureAtlasEnabled(enabled bool) {
+	rm.textureManager.setAtlasEnabled(enabled)
 }
 
 // 卸载纹理
 func (rm *ResourceManager) UnloadTexture(path string) {
 	rm.textureManager.UnloadTexture(path)
+	rm.forget(resourceKey{kind: resourceKindTexture, path: path})
 }
 
 // 获取字体
 func (rm *ResourceManager) GetFont(path string, size int) *ttf.Font {
-	return rm.fontManager.GetFont(path, size)
+	font := rm.fontManager.GetFont(path, size)
+	if font != nil {
+		rm.acquire(resourceKey{kind: resourceKindFont, path: path, size: size})
+	}
+	return font
 }
 
 // 卸载字体
 func (rm *ResourceManager) UnloadFont(path string, size int) {
 	rm.fontManager.UnloadFont(path, size)
+	rm.forget(resourceKey{kind: resourceKindFont, path: path, size: size})
 }
 
 // 获取音效
 func (rm *ResourceManager) GetSound(path string) *[]IAudio {
-	return rm.audioManager.GetSound(path)
+	sounds := rm.audioManager.GetSound(path)
+	if sounds != nil {
+		rm.acquire(resourceKey{kind: resourceKindSound, path: path})
+	}
+	return sounds
 }
 
 // 加载音效
 func (rm *ResourceManager) LoadSound(path string) *[]IAudio {
-	return rm.audioManager.loadSound(path)
+	sounds := rm.audioManager.loadSound(path)
+	if sounds != nil {
+		rm.acquire(resourceKey{kind: resourceKindSound, path: path})
+	}
+	return sounds
 }
 
 // 卸载音效
 func (rm *ResourceManager) UnloadSound(path string) {
 	rm.audioManager.UnloadSound(path)
+	rm.forget(resourceKey{kind: resourceKindSound, path: path})
 }
 
 // 获取音乐
 func (rm *ResourceManager) GetMusic(path string) *[]IAudio {
-	return rm.audioManager.GetMusic(path)
+	musics := rm.audioManager.GetMusic(path)
+	if musics != nil {
+		rm.RetainMusic(path)
+	}
+	return musics
+}
+
+// 记录当前作用域使用了音乐，用于继续播放上一个场景的音乐时保留音乐
+func (rm *ResourceManager) RetainMusic(path string) {
+	if _, ok := rm.audioManager.musics[path]; ok {
+		rm.acquire(resourceKey{kind: resourceKindMusic, path: path})
+	}
 }
 
 // 加载音乐
 func (rm *ResourceManager) LoadMusic(path string) *[]IAudio {
-	return rm.audioManager.loadMusic(path)
+	musics := rm.audioManager.loadMusic(path)
+	if musics != nil {
+		rm.RetainMusic(path)
+	}
+	return musics
 }
 
 // 卸载音乐
 func (rm *ResourceManager) UnloadMusic(path string) {
 	rm.audioManager.UnloadMusic(path)
+	rm.forget(resourceKey{kind: resourceKindMusic, path: path})
 }
 
 // 获取纹理大小
 func (rm *ResourceManager) GetTextureSize(path string) mgl32.Vec2 {
+	if rm.GetTexture(path) == nil {
+		slog.Error("texture not found", slog.String("path", path))
+		return mgl32.Vec2{}
+	}
 	return rm.textureManager.GetTextureSize(path)
 }
 
@@ -111,3 +194,28 @@ func (rm *ResourceManager) SetMusicVolume(volume float32) {
 func (rm *ResourceManager) GetMusicVolume() float32 {
 	return rm.audioManager.GetMusicVolume()
 }
+
+/**
+ * @brief 文件变化后重新加载对应的已缓存资源(纹理、字体、音频)，未缓存的资源不做处理
+ * @param path 发生变化的文件路径
+ * @return bool 是否有资源被重新加载
+ */
+func (rm *ResourceManager) ReloadFile(path string) bool {
+	// 依次尝试，同一路径只会存在于其中一个管理器中
+	if rm.textureManager.reloadTexture(path) {
+		rm.textureGeneration++
+		return true
+	}
+	return rm.fontManager.reloadFont(path) ||
+		rm.audioManager.reloadAudio(path)
+}
+
+// 获取纹理重新加载的次数，变化时说明有纹理内容被替换
+func (rm *ResourceManager) GetTextureGeneration() uint64 {
+	return rm.textureGeneration
+}
+
+// 判断两个路径是否指向同一文件，缓存键可能使用不同的分隔符
+func samePath(a, b string) bool {
+	return filepath.Clean(a) == filepath.Clean(b)
+}
@@ -0,0 +1,235 @@
+package resource
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/SunshineZzzz/purego-sdl3/sdl"
+)
+
+// 资源种类，用于引用计数与报告
+type resourceKind int
+
+const (
+	resourceKindTexture resourceKind = iota
+	resourceKindFont
+	resourceKindSound
+	resourceKindMusic
+)
+
+func (k resourceKind) String() string {
+	switch k {
+	case resourceKindTexture:
+		return "texture"
+	case resourceKindFont:
+		return "font"
+	case resourceKindSound:
+		return "sound"
+	case resourceKindMusic:
+		return "music"
+	}
+	return "unknown"
+}
+
+// 资源键，字体的size有效，其他资源为0
+type resourceKey struct {
+	kind resourceKind
+	path string
+	size int
+}
+
+/**
+ * @brief 资源作用域，记录某个场景使用过的资源。
+ *
+ * 作用域激活期间通过资源管理器获取的资源都会被记录，同一资源在一个作用域内只计一次引用。
+ * 作用域释放时引用计数减一，计数归零的资源被卸载，多个场景共用的资源(例如UI纹理)会保留。
+ */
+type ResourceScope struct {
+	// 作用域名称，一般为场景名称
+	name string
+	// 使用过的资源
+	keys map[resourceKey]struct{}
+	// 是否已释放
+	released bool
+}
+
+// 获取作用域名称
+func (rs *ResourceScope) GetName() string {
+	return rs.name
+}
+
+// 创建资源作用域
+func (rm *ResourceManager) NewScope(name string) *ResourceScope {
+	return &ResourceScope{
+		name: name,
+		keys: make(map[resourceKey]struct{}),
+	}
+}
+
+/**
+ * @brief 设置当前作用域，之后获取的资源都记录到该作用域
+ * @param scope 作用域，nil表示全局作用域(永不释放)
+ * @return *ResourceScope 之前的作用域，用于恢复
+ */
+func (rm *ResourceManager) SetCurrentScope(scope *ResourceScope) *ResourceScope {
+	prev := rm.currentScope
+	rm.currentScope = scope
+	return prev
+}
+
+// 获取当前作用域，nil表示全局作用域
+func (rm *ResourceManager) GetCurrentScope() *ResourceScope {
+	return rm.currentScope
+}
+
+// 记录当前作用域使用了资源
+func (rm *ResourceManager) acquire(key resourceKey) {
+	rm.acquireInScope(rm.currentScope, key)
+}
+
+// 记录指定作用域使用了资源
+func (rm *ResourceManager) acquireInScope(scope *ResourceScope, key resourceKey) {
+	if scope == nil {
+		scope = rm.globalScope
+	}
+	if scope.released {
+		slog.Warn("acquire resource in released scope", slog.String("scope", scope.name), slog.String("path", key.path))
+		return
+	}
+	if _, ok := scope.keys[key]; ok {
+		return
+	}
+	scope.keys[key] = struct{}{}
+	rm.refCounts[key]++
+}
+
+/**
+ * @brief 释放作用域，引用计数归零的资源被卸载
+ * @param scope 作用域
+ * @return int 卸载的资源数量
+ */
+func (rm *ResourceManager) ReleaseScope(scope *ResourceScope) int {
+	if scope == nil || scope == rm.globalScope || scope.released {
+		return 0
+	}
+	scope.released = true
+	if rm.currentScope == scope {
+		rm.currentScope = nil
+	}
+
+	unloaded := 0
+	for key := range scope.keys {
+		count, ok := rm.refCounts[key]
+		if !ok {
+			// 已经被手动卸载
+			continue
+		}
+		if count > 1 {
+			rm.refCounts[key] = count - 1
+			continue
+		}
+		delete(rm.refCounts, key)
+		rm.unloadByKey(key)
+		unloaded++
+	}
+	scope.keys = nil
+	slog.Debug("resource scope released", slog.String("scope", scope.name), slog.Int("unloaded", unloaded))
+	return unloaded
+}
+
+// 卸载资源
+func (rm *ResourceManager) unloadByKey(key resourceKey) {
+	switch key.kind {
+	case resourceKindTexture:
+		rm.textureManager.UnloadTexture(key.path)
+	case resourceKindFont:
+		rm.fontManager.UnloadFont(key.path, key.size)
+	case resourceKindSound:
+		rm.audioManager.UnloadSound(key.path)
+	case resourceKindMusic:
+		rm.audioManager.UnloadMusic(key.path)
+	}
+}
+
+// 资源被手动卸载时清除引用计数
+func (rm *ResourceManager) forget(key resourceKey) {
+	delete(rm.refCounts, key)
+}
+
+// 常驻资源信息
+type ResidentAsset struct {
+	// 资源种类
+	Kind string
+	// 资源路径
+	Path string
+	// 字体大小，其他资源为0
+	Size int
+	// 引用计数(使用该资源的作用域数量)
+	RefCount int
+	// 估算的内存占用(字节)，纹理按RGBA计算
+	Bytes int64
+}
+
+// 获取常驻资源列表，按内存占用从大到小排序
+func (rm *ResourceManager) GetResidentAssets() []ResidentAsset {
+	assets := make([]ResidentAsset, 0)
+	for path, texture := range rm.textureManager.textures {
+		var w, h float32
+		sdl.GetTextureSize(texture, &w, &h)
+		key := resourceKey{kind: resourceKindTexture, path: path}
+		assets = append(assets, ResidentAsset{Kind: key.kind.String(), Path: path, RefCount: rm.refCounts[key], Bytes: int64(w) * int64(h) * 4})
+	}
+	// 图集中的图片按自身大小计算
+	for path, region := range rm.textureManager.regions {
+		key := resourceKey{kind: resourceKindTexture, path: path}
+		assets = append(assets, ResidentAsset{Kind: key.kind.String(), Path: path, RefCount: rm.refCounts[key], Bytes: int64(region.rect.W) * int64(region.rect.H) * 4})
+	}
+	for hash, fk := range rm.fontManager.keys {
+		key := resourceKey{kind: resourceKindFont, path: fk.path, size: fk.size}
+		assets = append(assets, ResidentAsset{Kind: key.kind.String(), Path: fk.path, Size: fk.size, RefCount: rm.refCounts[key], Bytes: int64(len(rm.fontManager.datas[hash]))})
+	}
+	addAudios := func(kind resourceKind, cache map[string]*[]IAudio) {
+		for path, audios := range cache {
+			var bytes int64
+			for _, audio := range *audios {
+				bytes += int64(audio.GetDataSize())
+			}
+			key := resourceKey{kind: kind, path: path}
+			assets = append(assets, ResidentAsset{Kind: kind.String(), Path: path, RefCount: rm.refCounts[key], Bytes: bytes})
+		}
+	}
+	addAudios(resourceKindSound, rm.audioManager.sounds)
+	addAudios(resourceKindMusic, rm.audioManager.musics)
+
+	sort.Slice(assets, func(i, j int) bool {
+		if assets[i].Bytes != assets[j].Bytes {
+			return assets[i].Bytes > assets[j].Bytes
+		}
+		return assets[i].Path < assets[j].Path
+	})
+	return assets
+}
+
+// 生成常驻资源报告文本，用于调试
+func (rm *ResourceManager) Report() string {
+	assets := rm.GetResidentAssets()
+	var total int64
+	var sb strings.Builder
+	for _, asset := range assets {
+		total += asset.Bytes
+		name := asset.Path
+		if asset.Size > 0 {
+			name = fmt.Sprintf("%s@%d", asset.Path, asset.Size)
+		}
+		fmt.Fprintf(&sb, "%-8s %10.1f KB  refs=%d  %s\n", asset.Kind, float64(asset.Bytes)/1024.0, asset.RefCount, name)
+	}
+	fmt.Fprintf(&sb, "%d resident assets, %.2f MB total\n", len(assets), float64(total)/1024.0/1024.0)
+	return sb.String()
+}
+
+// 输出常驻资源报告到日志
+func (rm *ResourceManager) LogReport() {
+	slog.Info("resident assets report\n" + rm.Report())
+}
@@ -0,0 +1,191 @@
+package resource
+
+import (
+	"log/slog"
+
+	"github.com/SunshineZzzz/purego-sdl3/sdl"
+)
+
+const (
+	// 图集尺寸
+	atlasSize = 1024
+	// 可以放入图集的图片最大边长，更大的图片(背景、瓦片集)单独创建纹理
+	atlasMaxImageSize = 256
+	// 图片四周留出的透明像素，避免缩放、旋转时采样到相邻图片
+	atlasPadding = 1
+)
+
+/**
+ * @brief 纹理图集，将多张小图片合并到一张纹理中，连续绘制同一图集中的图片时SDL可以合批。
+ *
+ * 使用简单的货架算法(shelf packing)分配空间：图片从左到右依次放置，
+ * 当前行放不下时换到新的一行，行高为该行中最高的图片。卸载的图片空间不回收，
+ * 图集中的图片全部卸载后图集被销毁。
+ */
+type textureAtlas struct {
+	// 图集纹理
+	texture *sdl.Texture
+	// 当前行的下一个放置位置
+	cursorX, cursorY int32
+	// 当前行的高度
+	shelfHeight int32
+	// 图集中的图片数量
+	count int
+}
+
+// 图集中的一张图片
+type atlasRegion struct {
+	// 所在图集
+	atlas *textureAtlas
+	// 图片在图集中的区域(不含留白)
+	rect sdl.FRect
+}
+
+// 创建图集
+func newTextureAtlas(renderer *sdl.Renderer) *textureAtlas {
+	texture := sdl.CreateTexture(renderer, sdl.PixelFormatRGBA32, sdl.TextureAccessStatic, atlasSize, atlasSize)
+	if texture == nil {
+		slog.Error("create texture atlas error", slog.String("error", sdl.GetError()))
+		return nil
+	}
+	if !sdl.SetTextureBlendMode(texture, sdl.BlendModeBlend) {
+		slog.Warn("set texture atlas blend mode error", slog.String("error", sdl.GetError()))
+	}
+	if !sdl.SetTextureScaleMode(texture, sdl.ScaleModeNearest) {
+		slog.Warn("set texture atlas scale mode error", slog.String("error", sdl.GetError()))
+	}
+	slog.Debug("texture atlas created", slog.Int("size", atlasSize))
+	return &textureAtlas{texture: texture}
+}
+
+// 图片是否适合放入图集
+func canPackInAtlas(w, h int32) bool {
+	return w > 0 && h > 0 && w <= atlasMaxImageSize && h <= atlasMaxImageSize
+}
+
+/**
+ * @brief 在图集中分配空间，包含四周的留白
+ * @param w 图片宽度
+ * @param h 图片高度
+ * @return sdl.Rect 分配的区域(含留白)
+ * @return bool 图集是否还有空间
+ */
+func (ta *textureAtlas) allocate(w, h int32) (sdl.Rect, bool) {
+	w += atlasPadding * 2
+	h += atlasPadding * 2
+	if ta.cursorX+w > atlasSize {
+		// 换行
+		ta.cursorX = 0
+		ta.cursorY += ta.shelfHeight
+		ta.shelfHeight = 0
+	}
+	if ta.cursorY+h > atlasSize {
+		return sdl.Rect{}, false
+	}
+	rect := sdl.Rect{X: ta.cursorX, Y: ta.cursorY, W: w, H: h}
+	ta.cursorX += w
+	ta.shelfHeight = max(ta.shelfHeight, h)
+	return rect, true
+}
+
+/**
+ * @brief 将图片上传到图集的指定区域
+ * @param rect 分配的区域(含留白)
+ * @param surface 图片
+ * @return bool 是否成功
+ */
+func (ta *textureAtlas) upload(rect sdl.Rect, surface *sdl.Surface) bool {
+	// 新建的图片像素全部为0(透明)，将原图拷贝到中间，四周即为透明留白
+	padded := sdl.CreateSurface(rect.W, rect.H, sdl.PixelFormatRGBA32)
+	if padded == nil {
+		slog.Error("create atlas surface error", slog.String("error", sdl.GetError()))
+		return false
+	}
+	defer sdl.DestroySurface(padded)
+
+	// 直接拷贝像素而不是混合
+	sdl.SetSurfaceBlendMode(surface, sdl.BlendModeNone)
+	dstRect := sdl.Rect{X: atlasPadding, Y: atlasPadding, W: surface.W, H: surface.H}
+	if !sdl.BlitSurface(surface, nil, padded, &dstRect) {
+		slog.Error("blit atlas surface error", slog.String("error", sdl.GetError()))
+		return false
+	}
+	if !sdl.UpdateTexture(ta.texture, &rect, padded.Pixels, padded.Pitch) {
+		slog.Error("update texture atlas error", slog.String("error", sdl.GetError()))
+		return false
+	}
+	return true
+}
+
+// 销毁图集
+func (ta *textureAtlas) destroy() {
+	sdl.DestroyTexture(ta.texture)
+	ta.texture = nil
+	slog.Debug("texture atlas destroyed")
+}
+
+/**
+ * @brief 将图片放入图集，当前图集放不下时创建新图集
+ * @param path 纹理文件路径
+ * @param surface 图片
+ * @return bool 是否成功，失败时调用者应单独创建纹理
+ */
+func (tm *textureManager) packSurface(path string, surface *sdl.Surface) bool {
+	if !tm.atlasEnabled || !canPackInAtlas(surface.W, surface.H) {
+		return false
+	}
+
+	var atlas *textureAtlas
+	var rect sdl.Rect
+	ok := false
+	if len(tm.atlases) > 0 {
+		atlas = tm.atlases[len(tm.atlases)-1]
+		rect, ok = atlas.allocate(surface.W, surface.H)
+	}
+	if !ok {
+		atlas = newTextureAtlas(tm.sdlRenderer)
+		if atlas == nil {
+			return false
+		}
+		tm.atlases = append(tm.atlases, atlas)
+		if rect, ok = atlas.allocate(surface.W, surface.H); !ok {
+			return false
+		}
+	}
+	if !atlas.upload(rect, surface) {
+		return false
+	}
+
+	atlas.count++
+	tm.regions[path] = &atlasRegion{
+		atlas: atlas,
+		rect: sdl.FRect{
+			X: float32(rect.X + atlasPadding),
+			Y: float32(rect.Y + atlasPadding),
+			W: float32(surface.W),
+			H: float32(surface.H),
+		},
+	}
+	slog.Debug("texture packed into atlas", slog.String("path", path), slog.Any("rect", tm.regions[path].rect))
+	return true
+}
+
+// 从图集中移除图片，图集为空时销毁
+func (tm *textureManager) removeRegion(path string) {
+	region, ok := tm.regions[path]
+	if !ok {
+		return
+	}
+	delete(tm.regions, path)
+	region.atlas.count--
+	if region.atlas.count > 0 {
+		return
+	}
+	region.atlas.destroy()
+	for i, atlas := range tm.atlases {
+		if atlas == region.atlas {
+			tm.atlases = append(tm.atlases[:i], tm.atlases[i+1:]...)
+			break
+		}
+	}
+}
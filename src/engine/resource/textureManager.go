@@ -2,6 +2,7 @@ package resource
 
 import (
 	"log/slog"
+	"runtime"
 
 	"github.com/SunshineZzzz/purego-sdl3/img"
 	"github.com/SunshineZzzz/purego-sdl3/sdl"
@@ -12,8 +13,14 @@ import (
 type textureManager struct {
 	// 渲染器
 	sdlRenderer *sdl.Renderer
-	// 纹理缓存
+	// 纹理缓存，单独创建纹理的图片
 	textures map[string]*sdl.Texture
+	// 是否将小图片打包到图集
+	atlasEnabled bool
+	// 图集
+	atlases []*textureAtlas
+	// 打包到图集中的图片，路径 -> 图集区域
+	regions map[string]*atlasRegion
 }
 
 // 创建纹理管理器
@@ -24,8 +31,11 @@ func NewTextureManager(renderer *sdl.Renderer) *textureManager {
 
 	slog.Debug("texture manager init")
 	return &textureManager{
-		sdlRenderer: renderer,
-		textures:    make(map[string]*sdl.Texture),
+		sdlRenderer:  renderer,
+		textures:     make(map[string]*sdl.Texture),
+		atlasEnabled: true,
+		atlases:      make([]*textureAtlas, 0),
+		regions:      make(map[string]*atlasRegion),
 	}
 }
 
@@ -35,39 +45,111 @@ func (tm *textureManager) Clear() {
 		sdl.DestroyTexture(texture)
 	}
 	tm.textures = make(map[string]*sdl.Texture)
+	for _, atlas := range tm.atlases {
+		atlas.destroy()
+	}
+	tm.atlases = make([]*textureAtlas, 0)
+	tm.regions = make(map[string]*atlasRegion)
 
 	slog.Debug("texture manager clear")
 }
 
+// 设置是否将小图片打包到图集，只影响之后加载的纹理
+func (tm *textureManager) setAtlasEnabled(enabled bool) {
+	tm.atlasEnabled = enabled
+}
+
+// 纹理是否已缓存
+func (tm *textureManager) has(path string) bool {
+	if _, ok := tm.textures[path]; ok {
+		return true
+	}
+	_, ok := tm.regions[path]
+	return ok
+}
+
 // 加载纹理
-func (tm *textureManager) loadTexture(path string) *sdl.Texture {
-	if texture, ok := tm.textures[path]; ok {
-		return texture
+func (tm *textureManager) loadTexture(path string) bool {
+	if tm.has(path) {
+		return true
 	}
 
-	texture := img.LoadTexture(tm.sdlRenderer, path)
-	if texture == nil {
+	surface := tm.loadSurfaceFile(path)
+	if surface == nil {
 		slog.Error("load texture error", slog.String("path", path), slog.String("error", sdl.GetError()))
+		return false
+	}
+	return tm.addSurface(path, surface)
+}
+
+// 通过虚拟文件系统读取并解码图片
+func (tm *textureManager) loadSurfaceFile(path string) *sdl.Surface {
+	data, err := defaultVFS.ReadFile(path)
+	if err != nil {
+		slog.Error("read texture file error", slog.String("path", path), slog.String("error", err.Error()))
 		return nil
 	}
+	surface := img.LoadIO(sdl.IOFromConstMem(data), true)
+	runtime.KeepAlive(data)
+	return surface
+}
+
+// 将解码的图片放入图集或上传为单独的纹理并放入缓存，图片随后被释放
+func (tm *textureManager) addSurface(path string, surface *sdl.Surface) bool {
+	defer sdl.DestroySurface(surface)
+	if tm.has(path) {
+		// 预加载期间已经被按需加载
+		return true
+	}
+	if tm.packSurface(path, surface) {
+		return true
+	}
+
+	texture := tm.createTexture(path, surface)
+	if texture == nil {
+		return false
+	}
+	tm.textures[path] = texture
+	return true
+}
 
+// 将图片上传为单独的纹理
+func (tm *textureManager) createTexture(path string, surface *sdl.Surface) *sdl.Texture {
+	texture := sdl.CreateTextureFromSurface(tm.sdlRenderer, surface)
+	if texture == nil {
+		slog.Error("create texture from surface error", slog.String("path", path), slog.String("error", sdl.GetError()))
+		return nil
+	}
 	// 载入纹理时，设置纹理缩放模式为最邻近插值(必不可少，否则TileLayer渲染中会出现边缘空隙/模糊)
 	if !sdl.SetTextureScaleMode(texture, sdl.ScaleModeNearest) {
 		slog.Warn("set texture scale mode error", slog.String("path", path), slog.String("error", sdl.GetError()))
 	}
-
-	tm.textures[path] = texture
 	return texture
 }
 
-// 获取纹理
-func (tm *textureManager) GetTexture(path string) *sdl.Texture {
-	if texture, ok := tm.textures[path]; ok {
-		return texture
+/**
+ * @brief 获取纹理及图片在纹理中的区域
+ * @param path 纹理文件路径
+ * @return *sdl.Texture 纹理，图片被打包时为图集纹理
+ * @return *sdl.FRect 图片在图集中的区域，单独的纹理为nil
+ */
+func (tm *textureManager) GetTextureRegion(path string) (*sdl.Texture, *sdl.FRect) {
+	if !tm.has(path) {
+		slog.Debug("texture not in cache, try to load", slog.String("path", path))
+		if !tm.loadTexture(path) {
+			return nil, nil
+		}
+	}
+	if region, ok := tm.regions[path]; ok {
+		return region.atlas.texture, &region.rect
 	}
+	return tm.textures[path], nil
+}
 
-	slog.Debug("texture not in cache, try to load", slog.String("path", path))
-	return tm.loadTexture(path)
+// 获取纹理，图片被打包时返回图集纹理，需要配合GetTextureRegion获取区域
+func (tm *textureManager) GetTexture(path string) *sdl.Texture {
+	texture, _ := tm.GetTextureRegion(path)
+	return texture
 }
 
 // 卸载纹理
@@ -78,16 +160,86 @@ func (tm *textureManager) UnloadTexture(path string) {
 		slog.Debug("unload texture", slog.String("path", path))
 		return
 	}
+	if _, ok := tm.regions[path]; ok {
+		tm.removeRegion(path)
+		slog.Debug("unload atlas texture", slog.String("path", path))
+		return
+	}
 	slog.Warn("texture not in cache , can not unload", slog.String("path", path))
 }
 
-// 获取纹理大小
+/**
+ * @brief 重新加载已缓存的纹理，替换缓存中的条目，精灵通过纹理Id获取纹理，因此会自动使用新纹理
+ * @param path 纹理文件路径
+ * @return bool 是否有纹理被重新加载
+ */
+func (tm *textureManager) reloadTexture(path string) bool {
+	reloaded := false
+	for key, old := range tm.textures {
+		if !samePath(key, path) {
+			continue
+		}
+		surface := tm.loadSurfaceFile(key)
+		if surface == nil {
+			// 加载失败时保留旧纹理，例如编辑器尚未写完文件
+			slog.Error("reload texture error", slog.String("path", key), slog.String("error", sdl.GetError()))
+			continue
+		}
+		texture := tm.createTexture(key, surface)
+		sdl.DestroySurface(surface)
+		if texture == nil {
+			continue
+		}
+		sdl.DestroyTexture(old)
+		tm.textures[key] = texture
+		reloaded = true
+		slog.Info("texture reloaded", slog.String("path", key))
+	}
+	// 尺寸变化时会重新分配区域，先收集路径再处理，避免遍历中修改map
+	atlasKeys := make([]string, 0)
+	for key := range tm.regions {
+		if samePath(key, path) {
+			atlasKeys = append(atlasKeys, key)
+		}
+	}
+	for _, key := range atlasKeys {
+		region := tm.regions[key]
+		surface := tm.loadSurfaceFile(key)
+		if surface == nil {
+			slog.Error("reload texture error", slog.String("path", key), slog.String("error", sdl.GetError()))
+			continue
+		}
+		if float32(surface.W) == region.rect.W && float32(surface.H) == region.rect.H {
+			// 尺寸不变，直接覆盖图集中的区域
+			rect := sdl.Rect{
+				X: int32(region.rect.X) - atlasPadding,
+				Y: int32(region.rect.Y) - atlasPadding,
+				W: surface.W + atlasPadding*2,
+				H: surface.H + atlasPadding*2,
+			}
+			region.atlas.upload(rect, surface)
+			sdl.DestroySurface(surface)
+		} else {
+			// 尺寸变化，重新分配
+			tm.removeRegion(key)
+			tm.addSurface(key, surface)
+		}
+		reloaded = true
+		slog.Info("texture reloaded", slog.String("path", key))
+	}
+	return reloaded
+}
+
+// 获取纹理大小，图片被打包时为图片本身的大小
 func (tm *textureManager) GetTextureSize(path string) mgl32.Vec2 {
-	texture := tm.GetTexture(path)
+	texture, region := tm.GetTextureRegion(path)
 	if texture == nil {
 		slog.Error("texture not found", slog.String("path", path))
 		return mgl32.Vec2{}
 	}
+	if region != nil {
+		return mgl32.Vec2{region.W, region.H}
+	}
 
 	var w, h float32
 	sdl.GetTextureSize(texture, &w, &h)
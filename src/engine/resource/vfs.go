@@ -0,0 +1,11 @@
+package resource
+
+import "sunny_land/src/engine/vfs"
+
+// 默认虚拟文件系统，资源加载器统一通过它读取文件
+var defaultVFS = vfs.GetVFS()
+
+// 获取默认虚拟文件系统
+func GetVFS() *vfs.VFS {
+	return defaultVFS
+}
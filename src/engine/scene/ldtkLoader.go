@@ -0,0 +1,470 @@
+package scene
+
+import (
+	"log/slog"
+	"strings"
+
+	"sunny_land/src/engine/component"
+	"sunny_land/src/engine/object"
+	"sunny_land/src/engine/physics"
+	"sunny_land/src/engine/render"
+	"sunny_land/src/engine/resource"
+	"sunny_land/src/engine/utils"
+	emath "sunny_land/src/engine/utils/math"
+
+	"github.com/SunshineZzzz/purego-sdl3/sdl"
+	"github.com/bitly/go-simplejson"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+/**
+ * @brief LDtk关卡加载器，与LevelLoader(Tiled)产生相同的运行时对象。
+ *
+ * 地图路径格式："assets/maps/world.ldtk#Level_0"，#后为关卡标识符，省略时加载第一个关卡。
+ * 对应关系：
+ * 1. IntGrid图层 -> TileLayerComponent，IntGrid值的标识符决定瓦片类型
+ *    (solid、unisolid、hazard、ladder、slope_0_1 ...)，自动图块作为瓦片精灵图
+ * 2. Tiles图层 -> TileLayerComponent，瓦片类型为普通瓦片
+ * 3. Entities图层 -> GameObject，实体字段与Tiled自定义属性同名同义
+ *    (name、tag、animation、sound、health、gravity、ai、trigger)
+ * 4. 关卡字段 -> LevelProperties，关卡背景色 -> BackgroundColor
+ */
+type LDtkLoader struct {
+	// 复用Tiled加载器的属性及辅助方法
+	LevelLoader
+	// 瓦片集路径，瓦片集uid -> 纹理路径
+	tilesetPaths map[int]string
+	// 图层定义，图层定义uid -> 图层定义json
+	layerDefs map[int]*simplejson.Json
+}
+
+// 确保LDtkLoader实现了ILevelLoader接口
+var _ ILevelLoader = (*LDtkLoader)(nil)
+
+// 创建LDtk关卡加载器
+func NewLDtkLoader() *LDtkLoader {
+	slog.Debug("LDtkLoader created")
+	return &LDtkLoader{
+		LevelLoader:  *NewLevelLoader(),
+		tilesetPaths: make(map[int]string),
+		layerDefs:    make(map[int]*simplejson.Json),
+	}
+}
+
+// 加载关卡数据到指定的Scene对象中
+func (ldl *LDtkLoader) LoadLevel(mapPath string, scene IScene) bool {
+	// 分离项目文件路径和关卡标识符
+	projectPath, levelId, _ := strings.Cut(mapPath, "#")
+
+	// 加载JSON文件
+	data, err := resource.GetVFS().ReadFile(projectPath)
+	if err != nil {
+		slog.Error("Failed to read ldtk file", slog.String("mapPath", projectPath), slog.Any("error", err))
+		return false
+	}
+
+	// 解析JSON数据
+	root, err := simplejson.NewJson(data)
+	if err != nil {
+		slog.Error("Failed to parse ldtk file", slog.String("mapPath", projectPath), slog.Any("error", err))
+		return false
+	}
+	ldl.mapPath = projectPath
+
+	// 加载瓦片集定义
+	tilesets := root.Get("defs").Get("tilesets")
+	for i := 0; i < len(tilesets.MustArray()); i++ {
+		tileset := tilesets.GetIndex(i)
+		relPath := tileset.Get("relPath").MustString("")
+		if relPath == "" {
+			// 内置图标瓦片集等没有路径
+			continue
+		}
+		ldl.tilesetPaths[tileset.Get("uid").MustInt(0)] = ldl.resolvePath(relPath, ldl.mapPath)
+	}
+
+	// 加载图层定义，IntGrid值的标识符在图层定义中
+	layerDefs := root.Get("defs").Get("layers")
+	for i := 0; i < len(layerDefs.MustArray()); i++ {
+		layerDef := layerDefs.GetIndex(i)
+		ldl.layerDefs[layerDef.Get("uid").MustInt(0)] = layerDef
+	}
+
+	// 查找关卡
+	level := ldl.findLevel(root, levelId)
+	if level == nil {
+		slog.Error("ldtk level not found", slog.String("mapPath", projectPath), slog.String("level", levelId))
+		return false
+	}
+	// 外部关卡文件
+	if externalPath := level.Get("externalRelPath").MustString(""); externalPath != "" {
+		externalData, err := resource.GetVFS().ReadFile(ldl.resolvePath(externalPath, ldl.mapPath))
+		if err != nil {
+			slog.Error("Failed to read ldtk external level", slog.String("path", externalPath), slog.Any("error", err))
+			return false
+		}
+		if level, err = simplejson.NewJson(externalData); err != nil {
+			slog.Error("Failed to parse ldtk external level", slog.String("path", externalPath), slog.Any("error", err))
+			return false
+		}
+	}
+
+	// 加载关卡字段
+	props, fileProps := ldl.fieldsToMap(level.Get("fieldInstances"))
+	ldl.levelProperties = ldl.buildLevelProperties(props, fileProps, level.Get("__bgColor").MustString(""))
+
+	// 加载图层，LDtk中图层按从上到下的顺序存储，需要倒序添加到场景中
+	layers := level.Get("layerInstances")
+	if layers == nil || layers.MustArray() == nil {
+		slog.Error("lack layerInstances", slog.String("mapPath", mapPath))
+		return false
+	}
+	for i := len(layers.MustArray()) - 1; i >= 0; i-- {
+		layer := layers.GetIndex(i)
+		layerName := layer.Get("__identifier").MustString("Unnamed")
+		ldl.layerProperties[layerName] = make(map[string]any)
+		switch layer.Get("__type").MustString("") {
+		case "IntGrid", "Tiles", "AutoLayer":
+			ldl.loadGridLayer(layer, scene)
+		case "Entities":
+			if !layer.Get("visible").MustBool(true) {
+				slog.Info("layer is not visible", slog.String("layerName", layerName))
+				continue
+			}
+			ldl.loadEntityLayer(layer, scene)
+		default:
+			slog.Warn("unknown ldtk layer type", slog.String("layerType", layer.Get("__type").MustString("")))
+		}
+	}
+
+	slog.Info("ldtk level loaded", slog.String("mapPath", mapPath))
+	return true
+}
+
+// 根据标识符查找关卡，标识符为空时返回第一个关卡
+func (ldl *LDtkLoader) findLevel(root *simplejson.Json, levelId string) *simplejson.Json {
+	levels := root.Get("levels")
+	for i := 0; i < len(levels.MustArray()); i++ {
+		level := levels.GetIndex(i)
+		if levelId == "" || level.Get("identifier").MustString("") == levelId {
+			return level
+		}
+	}
+	return nil
+}
+
+/**
+ * @brief 将LDtk字段实例数组转换为 字段名 -> 字段值 映射
+ * @param fields fieldInstances json数组
+ * @return map[string]any 字段名 -> 字段值
+ * @return map[string]bool FilePath类型的字段名集合
+ */
+func (ldl *LDtkLoader) fieldsToMap(fields *simplejson.Json) (map[string]any, map[string]bool) {
+	result := make(map[string]any)
+	fileProps := make(map[string]bool)
+	for i := 0; i < len(fields.MustArray()); i++ {
+		field := fields.GetIndex(i)
+		name := field.Get("__identifier").MustString("")
+		value := field.Get("__value").Interface()
+		if name == "" || value == nil {
+			continue
+		}
+		result[name] = value
+		if field.Get("__type").MustString("") == "FilePath" {
+			fileProps[name] = true
+		}
+	}
+	return result, fileProps
+}
+
+// 加载网格图层(IntGrid、Tiles、AutoLayer)
+func (ldl *LDtkLoader) loadGridLayer(layer *simplejson.Json, scene IScene) {
+	layerName := layer.Get("__identifier").MustString("Unnamed")
+	gridSize := float32(layer.Get("__gridSize").MustInt(0))
+	columns := layer.Get("__cWid").MustInt(0)
+	rows := layer.Get("__cHei").MustInt(0)
+	if gridSize <= 0.0 || columns <= 0 || rows <= 0 {
+		slog.Error("ldtk layer has invalid size", slog.String("layerName", layerName))
+		return
+	}
+	tileSize := mgl32.Vec2{gridSize, gridSize}
+	mapSize := mgl32.Vec2{float32(columns), float32(rows)}
+
+	// 准备瓦片信息切片，瓦片数量 = 地图宽度 * 地图高度
+	tileInfos := make([]*physics.TileInfo, columns*rows)
+	for i := range tileInfos {
+		tileInfos[i] = &physics.TileInfo{Type: physics.TileTypeEmpty}
+	}
+
+	// 图块精灵图，自动图块和手动图块的格式相同
+	textureId := ldl.tilesetPaths[layer.Get("__tilesetDefUid").MustInt(-1)]
+	for _, key := range []string{"autoLayerTiles", "gridTiles"} {
+		tiles := layer.Get(key)
+		for i := 0; i < len(tiles.MustArray()); i++ {
+			tile := tiles.GetIndex(i)
+			px := tile.Get("px")
+			src := tile.Get("src")
+			index := (px.GetIndex(1).MustInt(0)/int(gridSize))*columns + px.GetIndex(0).MustInt(0)/int(gridSize)
+			if index < 0 || index >= len(tileInfos) || textureId == "" {
+				continue
+			}
+			// 同一格子叠加多个图块时保留最上层的图块，只支持水平翻转
+			tileInfos[index].Sprite = render.NewSprite(textureId, &sdl.FRect{
+				X: float32(src.GetIndex(0).MustInt(0)),
+				Y: float32(src.GetIndex(1).MustInt(0)),
+				W: gridSize,
+				H: gridSize,
+			}, tile.Get("f").MustInt(0)&1 != 0)
+			tileInfos[index].Type = physics.TileTypeNormal
+		}
+	}
+
+	// IntGrid值决定瓦片类型
+	if intGrid, ok := layer.CheckGet("intGridCsv"); ok && len(intGrid.MustArray()) > 0 {
+		valueTypes := ldl.getIntGridValueTypes(layer)
+		for i := 0; i < len(intGrid.MustArray()) && i < len(tileInfos); i++ {
+			value := intGrid.GetIndex(i).MustInt(0)
+			if value == 0 {
+				continue
+			}
+			tileType, ok := valueTypes[value]
+			if !ok {
+				slog.Error("unknown ldtk int grid value", slog.String("layerName", layerName), slog.Int("value", value))
+				continue
+			}
+			// 没有图块的IntGrid格子(Sprite为nil)也参与碰撞，但是不渲染
+			tileInfos[i].Type = tileType
+		}
+	}
+
+	// 创建游戏对象
+	gameObject := object.NewGameObject(layerName, layerName)
+	tileLayerComp := component.NewTileLayerComponent(tileSize, mapSize, tileInfos)
+	tileLayerComp.SetOffset(mgl32.Vec2{
+		float32(layer.Get("__pxTotalOffsetX").MustInt(0)),
+		float32(layer.Get("__pxTotalOffsetY").MustInt(0)),
+	})
+	tileLayerComp.SetColor(emath.FColor{R: 1.0, G: 1.0, B: 1.0, A: float32(layer.Get("__opacity").MustFloat64(1.0))})
+	tileLayerComp.SetHidden(!layer.Get("visible").MustBool(true))
+	if gameObject.AddComponent(tileLayerComp) == nil {
+		slog.Error("add tile layer component failed", slog.String("layerName", layerName))
+		return
+	}
+	scene.AddGameObject(gameObject)
+	slog.Info("ldtk tile layer loaded", slog.String("layerName", layerName))
+}
+
+// 获取IntGrid值对应的瓦片类型，IntGrid值 -> 瓦片类型
+func (ldl *LDtkLoader) getIntGridValueTypes(layer *simplejson.Json) map[int]physics.TileType {
+	result := make(map[int]physics.TileType)
+	layerDef, ok := ldl.layerDefs[layer.Get("layerDefUid").MustInt(-1)]
+	if !ok {
+		slog.Error("ldtk layer definition not found", slog.String("layerName", layer.Get("__identifier").MustString("Unnamed")))
+		return result
+	}
+	values := layerDef.Get("intGridValues")
+	for i := 0; i < len(values.MustArray()); i++ {
+		value := values.GetIndex(i)
+		identifier := value.Get("identifier").MustString("")
+		result[value.Get("value").MustInt(0)] = tileTypeByName(identifier)
+	}
+	return result
+}
+
+// 根据名称获取瓦片类型，名称与Tiled中的瓦片属性一致，未知名称为普通瓦片
+func tileTypeByName(name string) physics.TileType {
+	switch strings.ToLower(name) {
+	case "solid":
+		return physics.TileTypeSolid
+	case "unisolid":
+		return physics.TileTypeUniSolid
+	case "hazard":
+		return physics.TileTypeHazard
+	case "ladder":
+		return physics.TileTypeLadder
+	case "slope_0_1":
+		return physics.TileTypeSlope_0_1
+	case "slope_1_0":
+		return physics.TileTypeSlope_1_0
+	case "slope_0_2":
+		return physics.TileTypeSlope_0_2
+	case "slope_2_1":
+		return physics.TileTypeSlope_2_1
+	case "slope_1_2":
+		return physics.TileTypeSlope_1_2
+	case "slope_2_0":
+		return physics.TileTypeSlope_2_0
+	default:
+		return physics.TileTypeNormal
+	}
+}
+
+// 加载实体图层
+func (ldl *LDtkLoader) loadEntityLayer(layer *simplejson.Json, scene IScene) {
+	layerName := layer.Get("__identifier").MustString("Unnamed")
+	layerOffset := mgl32.Vec2{
+		float32(layer.Get("__pxTotalOffsetX").MustInt(0)),
+		float32(layer.Get("__pxTotalOffsetY").MustInt(0)),
+	}
+
+	entities := layer.Get("entityInstances")
+	for i := 0; i < len(entities.MustArray()); i++ {
+		entity := entities.GetIndex(i)
+		fields, _ := ldl.fieldsToMap(entity.Get("fieldInstances"))
+		identifier := entity.Get("__identifier").MustString("Unnamed")
+		// 名称优先使用name字段，否则使用小写的实体标识符(例如"Player" -> "player")
+		name, ok := fields["name"].(string)
+		if !ok || name == "" {
+			name = strings.ToLower(identifier)
+		}
+
+		// 实体盒子尺寸及左上角位置，px为锚点(pivot)位置
+		boxSize := mgl32.Vec2{
+			float32(entity.Get("width").MustInt(0)),
+			float32(entity.Get("height").MustInt(0)),
+		}
+		pivot := mgl32.Vec2{
+			float32(entity.Get("__pivot").GetIndex(0).MustFloat64(0.0)),
+			float32(entity.Get("__pivot").GetIndex(1).MustFloat64(0.0)),
+		}
+		boxPos := mgl32.Vec2{
+			float32(entity.Get("px").GetIndex(0).MustInt(0)),
+			float32(entity.Get("px").GetIndex(1).MustInt(0)),
+		}.Add(layerOffset).Sub(mgl32.Vec2{boxSize.X() * pivot.X(), boxSize.Y() * pivot.Y()})
+
+		gameObject := object.NewGameObject(name, name)
+		if tag, ok := fields["tag"].(string); ok && tag != "" {
+			gameObject.SetTag(tag)
+		}
+
+		// 没有图块的实体为触发区域，与Tiled中的矩形对象一致
+		tile, hasTile := entity.CheckGet("__tile")
+		textureId := ""
+		if hasTile {
+			textureId = ldl.tilesetPaths[tile.Get("tilesetUid").MustInt(-1)]
+		}
+		if textureId == "" {
+			if !ldl.addTriggerEntity(gameObject, boxPos, boxSize, fields, scene) {
+				continue
+			}
+			scene.AddGameObject(gameObject)
+			slog.Info("add game object to scene", slog.String("objectName", name))
+			continue
+		}
+
+		// 精灵图底边中点与实体盒子底边中点对齐，碰撞盒即实体盒子
+		srcRect := &sdl.FRect{
+			X: float32(tile.Get("x").MustInt(0)),
+			Y: float32(tile.Get("y").MustInt(0)),
+			W: float32(tile.Get("w").MustInt(0)),
+			H: float32(tile.Get("h").MustInt(0)),
+		}
+		srcSize := mgl32.Vec2{srcRect.W, srcRect.H}
+		position := mgl32.Vec2{
+			boxPos.X() + (boxSize.X()-srcSize.X())*0.5,
+			boxPos.Y() + boxSize.Y() - srcSize.Y(),
+		}
+
+		transformCom := component.NewTransformComponent(position, mgl32.Vec2{1.0, 1.0}, 0.0)
+		spriteCom := component.NewSpriteComponent(textureId, scene.GetResourceManager(), utils.AlignNone, srcRect, false)
+		if gameObject.AddComponent(transformCom) == nil || gameObject.AddComponent(spriteCom) == nil {
+			slog.Error("add transform or sprite component failed", slog.String("layerName", layerName))
+			continue
+		}
+		// 碰撞组件与物理组件
+		colliderCom := component.NewColliderComponent(physics.NewAABBCollider(boxSize), utils.AlignNone, boxPos.Sub(position), false, true)
+		gravity, _ := fields["gravity"].(bool)
+		physicsCom := component.NewPhysicsComponent(scene.GetContext().PhysicsEngine, 1.0, gravity)
+		if gameObject.AddComponent(colliderCom) == nil || gameObject.AddComponent(physicsCom) == nil {
+			slog.Error("add collider or physics component failed", slog.String("layerName", layerName))
+			continue
+		}
+		if solid, _ := fields["solid"].(bool); solid {
+			gameObject.SetTag("solid")
+		}
+
+		if !ldl.addEntityFields(gameObject, fields, position, srcSize, scene) {
+			continue
+		}
+
+		// 游戏对象添加到场景中
+		scene.AddGameObject(gameObject)
+		slog.Info("add game object to scene", slog.String("gameObjectName", name))
+	}
+}
+
+// 添加没有图块的触发区域实体
+func (ldl *LDtkLoader) addTriggerEntity(gameObject *object.GameObject, boxPos, boxSize mgl32.Vec2, fields map[string]any, scene IScene) bool {
+	transformCom := component.NewTransformComponent(boxPos, mgl32.Vec2{1.0, 1.0}, 0.0)
+	if gameObject.AddComponent(transformCom) == nil {
+		slog.Error("add transform component failed", slog.String("objectName", gameObject.GetName()))
+		return false
+	}
+	colliderCom := component.NewColliderComponent(physics.NewAABBCollider(boxSize), utils.AlignNone, mgl32.Vec2{0.0, 0.0}, false, true)
+	if gameObject.AddComponent(colliderCom) == nil {
+		slog.Error("add collider component failed", slog.String("objectName", gameObject.GetName()))
+		return false
+	}
+	trigger, ok := fields["trigger"].(bool)
+	colliderCom.SetTrigger(!ok || trigger)
+	physicsCom := component.NewPhysicsComponent(scene.GetContext().PhysicsEngine, 1.0, false)
+	if gameObject.AddComponent(physicsCom) == nil {
+		slog.Error("add physics component failed", slog.String("objectName", gameObject.GetName()))
+		return false
+	}
+	return true
+}
+
+/**
+ * @brief 根据实体字段添加动画、音效、生命值及AI组件，字段格式与Tiled自定义属性一致。
+ * AI字段无效时不视为失败，与Tiled加载器相同。
+ * @param gameObject 游戏对象
+ * @param fields 实体字段
+ * @param position 游戏对象初始位置
+ * @param spriteSize 每一帧动画的尺寸
+ * @param scene 场景
+ * @return bool 是否成功
+ */
+func (ldl *LDtkLoader) addEntityFields(gameObject *object.GameObject, fields map[string]any, position, spriteSize mgl32.Vec2, scene IScene) bool {
+	name := gameObject.GetName()
+
+	if animation, ok := fields["animation"].(string); ok && animation != "" {
+		if !ldl.addAnimationProperty(gameObject, animation, spriteSize) {
+			return false
+		}
+	}
+
+	if sound, ok := fields["sound"].(string); ok && sound != "" {
+		soundJson, err := simplejson.NewJson([]byte(sound))
+		if err != nil {
+			slog.Error("parse sound json failed", slog.String("gameObjectName", name), slog.String("error", err.Error()))
+			return false
+		}
+		audioCom := component.NewAudioComponent(scene.GetContext().AudioPlayer, scene.GetContext().Camera)
+		if gameObject.AddComponent(audioCom) == nil {
+			slog.Error("add audio component failed", slog.String("gameObjectName", name))
+			return false
+		}
+		ldl.addSound(soundJson, audioCom)
+	}
+
+	if health, ok := fields["health"]; ok {
+		healthCom := component.NewHealthComponent(int(propertyToFloat(health, 1.0)), 2.0)
+		if gameObject.AddComponent(healthCom) == nil {
+			slog.Error("add health component failed", slog.String("gameObjectName", name))
+			return false
+		}
+	}
+
+	// 与Tiled加载器一致，AI字段有问题时只记录错误，实体仍然添加到场景中(没有AI组件)
+	if aiValue, ok := fields["ai"]; ok && aiValue != nil && aiValue != "" {
+		if ai, ok := aiValue.(string); !ok {
+			slog.Error("ai field is not a string", slog.String("gameObjectName", name), slog.Any("value", aiValue))
+		} else if aiJson, err := simplejson.NewJson([]byte(ai)); err != nil {
+			slog.Error("parse ai json failed", slog.String("gameObjectName", name), slog.String("error", err.Error()))
+		} else if !ldl.addAI(aiJson, gameObject, position) {
+			slog.Error("add ai component failed", slog.String("gameObjectName", name))
+		}
+	}
+	return true
+}
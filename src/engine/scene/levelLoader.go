@@ -3,13 +3,14 @@ package scene
 import (
 	"encoding/json"
 	"log/slog"
-	"os"
 	"path/filepath"
+	"strings"
 
 	"sunny_land/src/engine/component"
 	"sunny_land/src/engine/object"
 	"sunny_land/src/engine/physics"
 	"sunny_land/src/engine/render"
+	"sunny_land/src/engine/resource"
 	"sunny_land/src/engine/utils"
 	"sunny_land/src/engine/utils/def"
 	emath "sunny_land/src/engine/utils/math"
@@ -20,7 +21,32 @@ import (
 	"github.com/go-gl/mathgl/mgl32"
 )
 
-// 关卡加载器
+// 相机区域对象的类型(class)
+const cameraZoneClass = "camera_zone"
+
+// 关卡中闪烁光源的频率(次/秒)
+const lightFlickerSpeed = 4.0
+
+// 关卡加载器抽象，不同编辑器格式的加载器产生相同的运行时对象
+type ILevelLoader interface {
+	// 加载关卡数据到指定的Scene对象中
+	LoadLevel(string, IScene) bool
+	// 获取关卡属性，LoadLevel成功后有效
+	GetLevelProperties() *LevelProperties
+	// 获取指定图层的自定义属性，图层不存在时返回nil
+	GetLayerProperties(string) map[string]any
+}
+
+// 根据地图文件扩展名创建对应的关卡加载器，".ldtk"使用LDtk加载器，其余使用Tiled加载器
+func NewLevelLoaderByPath(mapPath string) ILevelLoader {
+	projectPath, _, _ := strings.Cut(mapPath, "#")
+	if strings.EqualFold(filepath.Ext(projectPath), ".ldtk") {
+		return NewLDtkLoader()
+	}
+	return NewLevelLoader()
+}
+
+// 关卡加载器(Tiled)
 type LevelLoader struct {
 	// 地图路径
 	mapPath string
@@ -30,20 +56,29 @@ type LevelLoader struct {
 	tileSize mgl32.Vec2
 	// 瓦片集数据
 	tilesetsData *rbt.Tree
+	// 关卡属性
+	levelProperties *LevelProperties
+	// 图层自定义属性，图层名称 -> (属性名 -> 属性值)
+	layerProperties map[string]map[string]any
 }
 
+// 确保LevelLoader实现了ILevelLoader接口
+var _ ILevelLoader = (*LevelLoader)(nil)
+
 // 创建关卡加载器
 func NewLevelLoader() *LevelLoader {
 	slog.Debug("LevelLoader created")
 	return &LevelLoader{
-		tilesetsData: rbt.NewWithIntComparator(),
+		tilesetsData:    rbt.NewWithIntComparator(),
+		levelProperties: newLevelProperties(),
+		layerProperties: make(map[string]map[string]any),
 	}
 }
 
 // 加载关卡数据到指定的Scene对象中
 func (ll *LevelLoader) LoadLevel(mapPath string, scene IScene) bool {
 	// 加载JSON文件
-	data, err := os.ReadFile(mapPath)
+	data, err := resource.GetVFS().ReadFile(mapPath)
 	if err != nil {
 		slog.Error("Failed to read level file", slog.String("mapPath", mapPath), slog.Any("error", err))
 		return false
@@ -66,6 +101,9 @@ func (ll *LevelLoader) LoadLevel(mapPath string, scene IScene) bool {
 		float32(root.Get("tileheight").MustInt(0)),
 	}
 
+	// 加载地图顶层属性
+	ll.loadLevelProperties(root)
+
 	// 加载tilesets数据
 	if root.Get("tilesets") == nil || root.Get("tilesets").MustArray() == nil {
 		slog.Error("lack tilesets", slog.String("mapPath", ll.mapPath))
@@ -94,37 +132,140 @@ func (ll *LevelLoader) LoadLevel(mapPath string, scene IScene) bool {
 		slog.Error("lack layers", slog.String("mapPath", ll.mapPath))
 		return false
 	}
-	layers := root.Get("layers")
+	// 根图层没有累计变换
+	ll.loadLayers(root.Get("layers"), newLayerTransform(), scene)
+
+	slog.Info("level loaded", slog.String("mapPath", ll.mapPath))
+	return true
+}
+
+/**
+ * @brief 图层累计变换，组图层(group)的属性会叠加到其子图层上。
+ *
+ * 偏移量相加，视差因子、不透明度、色调相乘，可见性取与，
+ * 绘制图层(自定义属性draw_layer)未设置时继承父图层。
+ */
+type layerTransform struct {
+	// 偏移量(offsetx/offsety)
+	offset mgl32.Vec2
+	// 视差因子(parallaxx/parallaxy)
+	parallax mgl32.Vec2
+	// 不透明度(opacity)
+	opacity float32
+	// 色调(tintcolor)
+	tint emath.FColor
+	// 是否可见(visible)
+	visible bool
+	// 绘制图层(draw_layer)，越大越靠前
+	drawLayer int
+}
+
+// 创建默认图层变换
+func newLayerTransform() layerTransform {
+	return layerTransform{
+		offset:   mgl32.Vec2{0.0, 0.0},
+		parallax: mgl32.Vec2{1.0, 1.0},
+		opacity:  1.0,
+		tint:     emath.FColor{R: 1.0, G: 1.0, B: 1.0, A: 1.0},
+		visible:  true,
+	}
+}
+
+// 将图层自身的属性叠加到父图层的累计变换上
+func (lt layerTransform) combine(layer *simplejson.Json) layerTransform {
+	result := layerTransform{
+		offset: lt.offset.Add(mgl32.Vec2{
+			float32(layer.Get("offsetx").MustFloat64(0.0)),
+			float32(layer.Get("offsety").MustFloat64(0.0)),
+		}),
+		parallax: emath.Mgl32Vec2MulElem(lt.parallax, mgl32.Vec2{
+			float32(layer.Get("parallaxx").MustFloat64(1.0)),
+			float32(layer.Get("parallaxy").MustFloat64(1.0)),
+		}),
+		opacity:   lt.opacity * float32(layer.Get("opacity").MustFloat64(1.0)),
+		tint:      lt.tint,
+		visible:   lt.visible && layer.Get("visible").MustBool(true),
+		drawLayer: lt.drawLayer,
+	}
+	if drawLayer, ok := propertyInt(layer, "draw_layer"); ok {
+		result.drawLayer = drawLayer
+	}
+	if tintString := layer.Get("tintcolor").MustString(""); tintString != "" {
+		if tint := parseTiledColor(tintString); tint != nil {
+			result.tint = emath.FColor{
+				R: lt.tint.R * tint.R,
+				G: lt.tint.G * tint.G,
+				B: lt.tint.B * tint.B,
+				A: lt.tint.A * tint.A,
+			}
+		} else {
+			slog.Error("parse layer tint color failed", slog.String("layerName", layer.Get("name").MustString("Unnamed")), slog.String("tintcolor", tintString))
+		}
+	}
+	return result
+}
+
+// 获取最终的颜色调制，不透明度合并到alpha通道
+func (lt layerTransform) color() emath.FColor {
+	return emath.FColor{R: lt.tint.R, G: lt.tint.G, B: lt.tint.B, A: lt.tint.A * lt.opacity}
+}
+
+/**
+ * @brief 加载图层数组，组图层递归加载其子图层。
+ * @param layers 图层json数组
+ * @param parent 父图层的累计变换
+ * @param scene 场景
+ */
+func (ll *LevelLoader) loadLayers(layers *simplejson.Json, parent layerTransform, scene IScene) {
 	for i := 0; i < len(layers.MustArray()); i++ {
 		layer := layers.GetIndex(i)
+		layerName := layer.Get("name").MustString("Unnamed")
 		// 获取个图层对象中的类型(type)字段
 		layerType := layer.Get("type").MustString("none")
-		if !layer.Get("visible").MustBool(false) {
-			slog.Info("layer is not visible", slog.String("layerName", layer.Get("name").MustString("Unnamed")))
-			continue
+		// 记录图层自定义属性，隐藏图层同样记录
+		ll.layerProperties[layerName] = propertiesToMap(layer)
+		// 叠加父图层变换
+		transform := parent.combine(layer)
+		if !transform.visible {
+			slog.Info("layer is not visible", slog.String("layerName", layerName))
 		}
 		// 更具图层类型决定加载方法
 		switch layerType {
+		case "group":
+			// 组图层，递归加载子图层
+			if children, ok := layer.CheckGet("layers"); ok {
+				ll.loadLayers(children, transform, scene)
+			}
 		case "imagelayer":
-			// 图像图层
-			ll.loadImageLayer(layer, scene)
+			// 图像图层，隐藏的图层也加载，但不渲染，方便运行时显示
+			ll.loadImageLayer(layer, transform, scene)
 		case "tilelayer":
-			// 图块图层，瓦片图层
-			ll.loadTileLayer(layer, scene)
+			// 图块图层，瓦片图层，同上
+			ll.loadTileLayer(layer, transform, scene)
 		case "objectgroup":
-			// 对象图层
-			ll.loadObjectLayer(layer, scene)
+			// 对象图层，隐藏的对象图层直接跳过
+			if !transform.visible {
+				continue
+			}
+			ll.loadObjectLayer(layer, transform, scene)
 		default:
 			slog.Warn("unknown layer type", slog.String("layerType", layerType))
 		}
 	}
+}
 
-	slog.Info("level loaded", slog.String("mapPath", ll.mapPath))
-	return true
+// 获取关卡属性，LoadLevel成功后有效
+func (ll *LevelLoader) GetLevelProperties() *LevelProperties {
+	return ll.levelProperties
+}
+
+// 获取指定图层的自定义属性，图层不存在时返回nil
+func (ll *LevelLoader) GetLayerProperties(layerName string) map[string]any {
+	return ll.layerProperties[layerName]
 }
 
 // 加载图像图层
-func (ll *LevelLoader) loadImageLayer(layer *simplejson.Json, scene IScene) {
+func (ll *LevelLoader) loadImageLayer(layer *simplejson.Json, transform layerTransform, scene IScene) {
 	// 获取图像图层名称
 	layerName := layer.Get("name").MustString("Unnamed")
 
@@ -137,16 +278,10 @@ func (ll *LevelLoader) loadImageLayer(layer *simplejson.Json, scene IScene) {
 	// 解析纹理路径为干净的相对路径
 	textureId := ll.resolvePath(texturePath, ll.mapPath)
 
-	// 获取图像偏移量
-	offset := mgl32.Vec2{
-		float32(layer.Get("offsetx").MustFloat64(0.0)),
-		float32(layer.Get("offsety").MustFloat64(0.0)),
-	}
-	// 获取视差因子
-	scrollFactor := mgl32.Vec2{
-		float32(layer.Get("parallaxx").MustFloat64(1.0)),
-		float32(layer.Get("parallaxy").MustFloat64(1.0)),
-	}
+	// 获取图像偏移量(含组图层累计偏移)
+	offset := transform.offset
+	// 获取视差因子(含组图层累计视差)
+	scrollFactor := transform.parallax
 
 	// 获取重复标志
 	repeat := emath.Vec2B{
@@ -159,6 +294,9 @@ func (ll *LevelLoader) loadImageLayer(layer *simplejson.Json, scene IScene) {
 	// 依次添加变换组件，视差组件
 	transformComp := component.NewTransformComponent(offset, mgl32.Vec2{1.0, 1.0}, 0.0)
 	parallaxComp := component.NewParallaxComponent(textureId, scrollFactor, repeat)
+	parallaxComp.SetColor(transform.color())
+	parallaxComp.SetDrawLayer(transform.drawLayer)
+	parallaxComp.SetHidden(!transform.visible)
 	if gameObject.AddComponent(transformComp) == nil {
 		slog.Error("add transform component failed", slog.String("layerName", layerName))
 		return
@@ -174,7 +312,7 @@ func (ll *LevelLoader) loadImageLayer(layer *simplejson.Json, scene IScene) {
 
 // 加载图块集
 func (ll *LevelLoader) loadTileSet(tilesetPath string, firstGId int) {
-	data, err := os.ReadFile(tilesetPath)
+	data, err := resource.GetVFS().ReadFile(tilesetPath)
 	if err != nil {
 		slog.Error("Failed to read tileset file", slog.String("tilesetPath", tilesetPath), slog.Any("error", err))
 		return
@@ -192,7 +330,7 @@ func (ll *LevelLoader) loadTileSet(tilesetPath string, firstGId int) {
 }
 
 // 加载图块图层
-func (ll *LevelLoader) loadTileLayer(layer *simplejson.Json, scene IScene) {
+func (ll *LevelLoader) loadTileLayer(layer *simplejson.Json, transform layerTransform, scene IScene) {
 	if layer == nil || layer.Get("data") == nil || layer.Get("data").MustArray() == nil {
 		slog.Error("lack tile layer data", slog.String("layerName", layer.Get("name").MustString("Unnamed")))
 		return
@@ -213,6 +351,11 @@ func (ll *LevelLoader) loadTileLayer(layer *simplejson.Json, scene IScene) {
 	gameObject := object.NewGameObject(layerName, layerName)
 	// 创建瓦片图层组件
 	tileLayerComp := component.NewTileLayerComponent(ll.tileSize, ll.mapSize, tileInfos)
+	tileLayerComp.SetOffset(transform.offset)
+	tileLayerComp.SetScrollFactor(transform.parallax)
+	tileLayerComp.SetColor(transform.color())
+	tileLayerComp.SetHidden(!transform.visible)
+	tileLayerComp.SetDrawLayer(transform.drawLayer)
 	// 游戏对象添加组件
 	if gameObject.AddComponent(tileLayerComp) == nil {
 		slog.Error("add tile layer component failed", slog.String("layerName", layerName))
@@ -320,7 +463,7 @@ func (ll *LevelLoader) getTileInfoByGId(gId int) *physics.TileInfo {
 }
 
 // 加载对象图层
-func (ll *LevelLoader) loadObjectLayer(layer *simplejson.Json, scene IScene) {
+func (ll *LevelLoader) loadObjectLayer(layer *simplejson.Json, transform layerTransform, scene IScene) {
 	if layer.Get("objects") == nil || layer.Get("objects").MustArray() == nil {
 		slog.Error("object layer has no objects", slog.String("layerName", layer.Get("name").MustString("Unnamed")))
 		return
@@ -343,6 +486,10 @@ func (ll *LevelLoader) loadObjectLayer(layer *simplejson.Json, scene IScene) {
 			} else if obj.Get("polygon").MustBool(false) {
 				// TODO: 多边形对象的处理方式
 				continue
+			} else if ll.isCameraZone(obj) {
+				// 相机区域只记录范围，不创建游戏对象
+				ll.addCameraZone(obj, transform)
+				continue
 			} else {
 				// 矩形对象
 				// 创建游戏对象并添加TransfromComponent
@@ -353,7 +500,7 @@ func (ll *LevelLoader) loadObjectLayer(layer *simplejson.Json, scene IScene) {
 				position := mgl32.Vec2{
 					float32(obj.Get("x").MustFloat64(0.0)),
 					float32(obj.Get("y").MustFloat64(0.0)),
-				}
+				}.Add(transform.offset)
 				rotation := obj.Get("rotation").MustFloat64(0.0)
 				// 创建变换组件
 				transformCom := component.NewTransformComponent(position, mgl32.Vec2{1.0, 1.0}, rotation)
@@ -387,6 +534,18 @@ func (ll *LevelLoader) loadObjectLayer(layer *simplejson.Json, scene IScene) {
 				if tag != nil {
 					gameObject.SetTag(tag.(string))
 				}
+				// 粒子发射器，发射区域为整个矩形，例如雨
+				if particles, ok := ll.getTileProperty(obj, "particles").(string); ok {
+					if !ll.addParticlesProperty(gameObject, particles, scene, dstSize, true) {
+						slog.Error("add particle emitter failed", slog.String("objectName", objectName))
+						continue
+					}
+				}
+				// 光源，位于矩形中心，例如没有图像的环境光源
+				if !ll.addLightProperty(gameObject, obj, nil, dstSize) {
+					slog.Error("add light failed", slog.String("objectName", objectName))
+					continue
+				}
 				// 添加到场景中
 				scene.AddGameObject(gameObject)
 				slog.Info("add game object to scene", slog.String("objectName", objectName))
@@ -398,11 +557,11 @@ func (ll *LevelLoader) loadObjectLayer(layer *simplejson.Json, scene IScene) {
 			slog.Error("tileInfo sprite has no textureId", slog.Int("gId", gid))
 			continue
 		}
-		// 获取构建变换组件的信息
+		// 获取构建变换组件的信息，加上图层偏移量
 		position := mgl32.Vec2{
 			float32(obj.Get("x").MustFloat64(0.0)),
 			float32(obj.Get("y").MustFloat64(0.0)),
-		}
+		}.Add(transform.offset)
 		// 获取绘制的目标大小
 		dstSize := mgl32.Vec2{
 			float32(obj.Get("width").MustFloat64(0.0)),
@@ -432,6 +591,14 @@ func (ll *LevelLoader) loadObjectLayer(layer *simplejson.Json, scene IScene) {
 		transformCom := component.NewTransformComponent(position, scale, float64(rotation))
 		// 创建渲染组件
 		spriteCom := component.NewSpriteComponentFromSprite(tileInfo.Sprite, scene.GetResourceManager(), utils.AlignNone)
+		// 绘制图层，对象自身的属性优先，没有则使用图层的绘制图层
+		drawLayer := transform.drawLayer
+		if value, ok := ll.getObjectProperty(obj, ll.getTileJsonByGId(gid), "draw_layer").(json.Number); ok {
+			if layerValue, err := value.Int64(); err == nil {
+				drawLayer = int(layerValue)
+			}
+		}
+		spriteCom.SetDrawOrder(drawLayer, 0.0)
 		// 添加到游戏对象中
 		if gameObject.AddComponent(transformCom) == nil {
 			slog.Error("add transform component failed", slog.String("layerName", layer.Get("name").MustString("Unnamed")))
@@ -508,21 +675,24 @@ func (ll *LevelLoader) loadObjectLayer(layer *simplejson.Json, scene IScene) {
 		// 获取动画信息并且设置
 		animation := ll.getTileProperty(tileJson, "animation")
 		if animation != nil {
-			// 解析成json对象
-			animationJson, err := simplejson.NewJson([]byte(animation.(string)))
-			if err != nil {
-				slog.Error("parse animation json failed", slog.String("layerName", layer.Get("name").MustString("Unnamed")), slog.String("error", err.Error()))
+			if !ll.addAnimationProperty(gameObject, animation.(string), srcSize) {
+				slog.Error("add animation failed", slog.String("layerName", layer.Get("name").MustString("Unnamed")))
 				continue
 			}
-			// 创建动画组件
-			animationCom := component.NewAnimationComponent()
-			// 添加动画组件到游戏对象中
-			if gameObject.AddComponent(animationCom) == nil {
-				slog.Error("add animation component failed", slog.String("layerName", layer.Get("name").MustString("Unnamed")))
+		}
+
+		// 获取粒子发射器信息并设置，发射器位于对象中心，对象自身的属性优先于图块集中的属性
+		if particles, ok := ll.getObjectProperty(obj, tileJson, "particles").(string); ok {
+			if !ll.addParticlesProperty(gameObject, particles, scene, dstSize, false) {
+				slog.Error("add particle emitter failed", slog.String("layerName", layer.Get("name").MustString("Unnamed")))
 				continue
 			}
-			// 添加动画到动画组件中
-			ll.addAnimation(animationJson, animationCom, srcSize)
+		}
+
+		// 获取光源信息并设置，光源位于对象中心
+		if !ll.addLightProperty(gameObject, obj, tileJson, dstSize) {
+			slog.Error("add light failed", slog.String("layerName", layer.Get("name").MustString("Unnamed")))
+			continue
 		}
 
 		// 获取音效消息并设置
@@ -560,6 +730,20 @@ func (ll *LevelLoader) loadObjectLayer(layer *simplejson.Json, scene IScene) {
 			}
 		}
 
+		// 获取AI信息并设置，对象自身的属性优先于图块集中的属性
+		// AI属性有问题时只记录错误，对象仍然添加到场景中(没有AI组件)，不会因此从关卡中消失
+		if aiValue := ll.getObjectProperty(obj, tileJson, "ai"); aiValue != nil {
+			if aiString, ok := aiValue.(string); !ok {
+				slog.Error("ai property is not a string", slog.String("gameObjectName", name), slog.Any("value", aiValue))
+			} else if aiJson, err := simplejson.NewJson([]byte(aiString)); err != nil {
+				// 解析AI json字符串失败
+				slog.Error("parse ai json failed", slog.String("gameObjectName", name), slog.String("error", err.Error()))
+			} else if !ll.addAI(aiJson, gameObject, position) {
+				// 添加AI组件到游戏对象中失败，例如行为未注册
+				slog.Error("add ai component failed", slog.String("gameObjectName", name))
+			}
+		}
+
 		// 游戏对象添加到场景中
 		scene.AddGameObject(gameObject)
 		slog.Info("add game object to scene", slog.String("gameObjectName", name))
@@ -589,6 +773,121 @@ func (ll *LevelLoader) addSound(soundJson *simplejson.Json, ac *component.AudioC
 	}
 }
 
+/**
+ * @brief 根据animation属性创建AnimationComponent并添加到游戏对象中。
+ * @param gameObject 游戏对象（动画组件添加到此对象）
+ * @param value 属性值，自定义的动画json字符串，或Aseprite导出的json精灵表路径(以.json结尾)
+ * @param spriteSize 每一帧动画的尺寸，仅自定义格式使用
+ * @return bool 是否添加成功
+ */
+func (ll *LevelLoader) addAnimationProperty(gameObject *object.GameObject, value string, spriteSize mgl32.Vec2) bool {
+	value = strings.TrimSpace(value)
+	isAseprite := strings.HasSuffix(strings.ToLower(value), ".json")
+	var animationJson *simplejson.Json
+	if !isAseprite {
+		// 解析成json对象
+		var err error
+		animationJson, err = simplejson.NewJson([]byte(value))
+		if err != nil {
+			slog.Error("parse animation json failed", slog.String("gameObjectName", gameObject.GetName()), slog.String("error", err.Error()))
+			return false
+		}
+	}
+
+	// 创建动画组件并添加到游戏对象中
+	animationCom := component.NewAnimationComponent()
+	if gameObject.AddComponent(animationCom) == nil {
+		slog.Error("add animation component failed", slog.String("gameObjectName", gameObject.GetName()))
+		return false
+	}
+	if isAseprite {
+		return animationCom.LoadAseprite(value)
+	}
+	// 添加动画到动画组件中
+	ll.addAnimation(animationJson, animationCom, spriteSize)
+	return true
+}
+
+/**
+ * @brief 根据particles属性创建ParticleEmitterComponent并添加到游戏对象中。
+ * @param gameObject 游戏对象（发射器添加到此对象）
+ * @param value 属性值，粒子发射器json文件路径(以.json结尾)，或json字符串
+ * @param scene 场景
+ * @param size 对象尺寸，发射器位于对象中心
+ * @param fillArea 是否以整个对象作为发射区域
+ * @return bool 是否添加成功
+ */
+func (ll *LevelLoader) addParticlesProperty(gameObject *object.GameObject, value string, scene IScene, size mgl32.Vec2, fillArea bool) bool {
+	value = strings.TrimSpace(value)
+	var config *component.ParticleEmitterConfig
+	if strings.HasSuffix(strings.ToLower(value), ".json") {
+		config = component.LoadParticleEmitterConfig(value)
+	} else {
+		var err error
+		if config, err = component.ParseParticleEmitterConfig([]byte(value)); err != nil {
+			slog.Error("parse particles json failed", slog.String("gameObjectName", gameObject.GetName()), slog.String("error", err.Error()))
+		}
+	}
+	if config == nil {
+		return false
+	}
+
+	emitterCom := component.NewParticleEmitterComponent(config, scene.GetResourceManager())
+	if gameObject.AddComponent(emitterCom) == nil {
+		return false
+	}
+	emitterCom.SetOffset(size.Mul(0.5))
+	if fillArea {
+		emitterCom.SetSpawnArea(size)
+	}
+	return true
+}
+
+/**
+ * @brief 根据light属性创建LightComponent并添加到游戏对象中，对象自身的属性优先于图块集中的属性。
+ *
+ * "light"         光源半径(像素)，没有此属性时不添加光源
+ * "light_color"   光源颜色(color类型，A为强度)，默认白色
+ * "light_texture" 光源纹理(cookie)路径，相对于可执行文件，默认为径向渐变
+ * "light_flicker" 闪烁幅度，0~1，默认不闪烁
+ * @param gameObject 游戏对象（光源添加到此对象）
+ * @param obj 对象json
+ * @param tileJson 图块json，可以为nil
+ * @param size 对象尺寸，光源位于对象中心
+ * @return bool 是否成功，没有light属性也视为成功
+ */
+func (ll *LevelLoader) addLightProperty(gameObject *object.GameObject, obj, tileJson *simplejson.Json, size mgl32.Vec2) bool {
+	value := ll.getObjectProperty(obj, tileJson, "light")
+	if value == nil {
+		return true
+	}
+	radius := float32(propertyToFloat(value, 0.0))
+	if radius <= 0.0 {
+		slog.Error("light radius must be positive", slog.String("gameObjectName", gameObject.GetName()), slog.Any("light", value))
+		return false
+	}
+	color := emath.FColor{R: 1.0, G: 1.0, B: 1.0, A: 1.0}
+	if colorString, ok := ll.getObjectProperty(obj, tileJson, "light_color").(string); ok && colorString != "" {
+		parsed := parseTiledColor(colorString)
+		if parsed == nil {
+			slog.Error("parse light color failed", slog.String("gameObjectName", gameObject.GetName()), slog.String("color", colorString))
+			return false
+		}
+		color = *parsed
+	}
+	texture, _ := ll.getObjectProperty(obj, tileJson, "light_texture").(string)
+
+	lightCom := component.NewLightComponent(strings.TrimSpace(texture), mgl32.Vec2{radius * 2.0, radius * 2.0}, color)
+	if gameObject.AddComponent(lightCom) == nil {
+		return false
+	}
+	lightCom.SetOffset(size.Mul(0.5))
+	if flicker := ll.getObjectProperty(obj, tileJson, "light_flicker"); flicker != nil {
+		lightCom.SetFlicker(float32(propertyToFloat(flicker, 0.0)), lightFlickerSpeed)
+	}
+	return true
+}
+
 /**
  * @brief 添加动画到指定的 AnimationComponent。
  * @param anim_json 动画json数据（自定义）
@@ -643,6 +942,39 @@ func (ll *LevelLoader) addAnimation(animJson *simplejson.Json, ac *component.Ani
 	}
 }
 
+/**
+ * @brief 根据AI json数据创建AIComponent并添加到游戏对象中。
+ * @param aiJson AI json数据（自定义），"behavior"字段为已注册的行为名称
+ * @param gameObject 游戏对象（AI组件添加到此对象）
+ * @param position 游戏对象的初始位置
+ * @return bool 是否添加成功
+ */
+func (ll *LevelLoader) addAI(aiJson *simplejson.Json, gameObject *object.GameObject, position mgl32.Vec2) bool {
+	if aiJson == nil || gameObject == nil {
+		slog.Error("ai json or game object is nil")
+		return false
+	}
+
+	behaviorName := aiJson.Get("behavior").MustString("")
+	if behaviorName == "" {
+		slog.Error("ai json has no behavior", slog.String("gameObjectName", gameObject.GetName()))
+		return false
+	}
+	behavior := component.CreateAIBehavior(behaviorName, aiJson, position)
+	if behavior == nil {
+		slog.Error("create ai behavior failed", slog.String("behavior", behaviorName))
+		return false
+	}
+
+	// AI组件初始化时会缓存其他组件，因此需要在其他组件之后添加
+	aiCom := component.NewAIComponent()
+	if gameObject.AddComponent(aiCom) == nil {
+		return false
+	}
+	aiCom.SetBehavior(behavior)
+	return true
+}
+
 /**
  * @brief 解析图片路径，合并地图路径和相对路径。例如：
  * 1. 文件路径："assets/maps/level1.tmj"
@@ -799,8 +1131,67 @@ func (ll *LevelLoader) getTileJsonByGId(gId int) *simplejson.Json {
 	return nil
 }
 
+// 判断对象是否为相机区域，Tiled 1.9之前为"type"字段，之后为"class"字段
+func (ll *LevelLoader) isCameraZone(obj *simplejson.Json) bool {
+	return obj.Get("type").MustString("") == cameraZoneClass || obj.Get("class").MustString("") == cameraZoneClass
+}
+
+// 将矩形对象记录为相机区域
+func (ll *LevelLoader) addCameraZone(obj *simplejson.Json, transform layerTransform) {
+	zone := render.CameraZone{
+		Name: obj.Get("name").MustString("Unnamed"),
+		Bounds: emath.Rect{
+			Position: mgl32.Vec2{
+				float32(obj.Get("x").MustFloat64(0.0)),
+				float32(obj.Get("y").MustFloat64(0.0)),
+			}.Add(transform.offset),
+			Size: mgl32.Vec2{
+				float32(obj.Get("width").MustFloat64(0.0)),
+				float32(obj.Get("height").MustFloat64(0.0)),
+			},
+		},
+		Transition: render.CAMERA_ZONE_TRANSITION,
+	}
+	if zone.Bounds.Size.X() <= 0.0 || zone.Bounds.Size.Y() <= 0.0 {
+		slog.Warn("camera zone has no size, ignored", slog.String("name", zone.Name))
+		return
+	}
+	if transition := ll.getTileProperty(obj, "transition"); transition != nil {
+		zone.Transition = propertyToFloat(transition, render.CAMERA_ZONE_TRANSITION)
+	}
+	ll.levelProperties.CameraZones = append(ll.levelProperties.CameraZones, zone)
+	slog.Debug("camera zone added", slog.String("name", zone.Name), slog.Any("bounds", zone.Bounds))
+}
+
+// 获取json数据中的整数属性
+func propertyInt(j *simplejson.Json, propName string) (int, bool) {
+	properties, ok := j.CheckGet("properties")
+	if !ok {
+		return 0, false
+	}
+	for i := 0; i < len(properties.MustArray()); i++ {
+		prop := properties.GetIndex(i)
+		if prop.Get("name").MustString("") == propName {
+			value, err := prop.Get("value").Int()
+			return value, err == nil
+		}
+	}
+	return 0, false
+}
+
+// 获取对象属性值，对象自身的属性优先，没有则使用瓦片json中的属性
+func (ll *LevelLoader) getObjectProperty(obj, tileJson *simplejson.Json, propName string) any {
+	if value := ll.getTileProperty(obj, propName); value != nil {
+		return value
+	}
+	return ll.getTileProperty(tileJson, propName)
+}
+
 // 根据json数据中的属性获取属性值
 func (ll *LevelLoader) getTileProperty(tileJson *simplejson.Json, propName string) any {
+	if tileJson == nil {
+		return nil
+	}
 	properties, ok := tileJson.CheckGet("properties")
 	if !ok {
 		return nil
@@ -0,0 +1,232 @@
+package scene
+
+import (
+	"encoding/json"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"sunny_land/src/engine/render"
+	emath "sunny_land/src/engine/utils/math"
+
+	"github.com/bitly/go-simplejson"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+/**
+ * @brief 关卡属性，来自地图顶层属性(Tiled中 地图->地图属性)。
+ *
+ * 内置属性：
+ * "music"         背景音乐路径(file类型相对于地图文件，string类型相对于可执行文件)
+ * "gravity"       重力加速度Y分量(像素/秒^2)
+ * "camera_bounds" 相机限制范围，json字符串：{"x":0,"y":0,"width":1456,"height":464}
+ * "time_limit"    关卡时间限制(秒)，0表示不限时
+ * "next_level"    下一关卡名称，例如"level2"
+ * "ambient_light" 环境光颜色(color类型)，设置后启用光照，用于洞穴或夜晚关卡
+ * 背景颜色使用Tiled原生的"backgroundcolor"字段。
+ * 相机区域来自对象层中类型(class)为"camera_zone"的矩形对象，可选属性"transition"为过渡时长(秒)。
+ * 其余属性保存在Properties中，由具体场景自行解释。
+ */
+type LevelProperties struct {
+	// 背景音乐路径，空表示未设置
+	Music string
+	// 重力加速度，nil表示使用物理引擎默认值
+	Gravity *mgl32.Vec2
+	// 相机限制范围，nil表示使用main层的世界尺寸
+	CameraBounds *emath.Rect
+	// 相机区域，玩家进入区域时相机限制范围过渡到区域范围
+	CameraZones []render.CameraZone
+	// 背景颜色，nil表示未设置
+	BackgroundColor *emath.FColor
+	// 环境光颜色，nil表示不使用光照
+	AmbientLight *emath.FColor
+	// 时间限制(秒)，0表示不限时
+	TimeLimit float64
+	// 下一关卡名称
+	NextLevel string
+	// 所有地图属性(原始值)，属性名 -> 属性值
+	Properties map[string]any
+}
+
+// 创建关卡属性
+func newLevelProperties() *LevelProperties {
+	return &LevelProperties{
+		Properties: make(map[string]any),
+	}
+}
+
+// 获取字符串类型属性
+func (lp *LevelProperties) GetString(name string, def string) string {
+	if value, ok := lp.Properties[name].(string); ok {
+		return value
+	}
+	return def
+}
+
+// 获取浮点类型属性
+func (lp *LevelProperties) GetFloat(name string, def float64) float64 {
+	return propertyToFloat(lp.Properties[name], def)
+}
+
+// 获取布尔类型属性
+func (lp *LevelProperties) GetBool(name string, def bool) bool {
+	if value, ok := lp.Properties[name].(bool); ok {
+		return value
+	}
+	return def
+}
+
+// 将Tiled属性数组转换为 属性名 -> 属性值 映射
+func propertiesToMap(owner *simplejson.Json) map[string]any {
+	result := make(map[string]any)
+	if owner == nil {
+		return result
+	}
+	properties, ok := owner.CheckGet("properties")
+	if !ok {
+		return result
+	}
+	for i := 0; i < len(properties.MustArray()); i++ {
+		prop := properties.GetIndex(i)
+		name := prop.Get("name").MustString("")
+		if name == "" {
+			continue
+		}
+		result[name] = prop.Get("value").Interface()
+	}
+	return result
+}
+
+// 获取Tiled属性数组中指定属性的类型
+func propertyType(owner *simplejson.Json, propName string) string {
+	properties, ok := owner.CheckGet("properties")
+	if !ok {
+		return ""
+	}
+	for i := 0; i < len(properties.MustArray()); i++ {
+		prop := properties.GetIndex(i)
+		if prop.Get("name").MustString("") == propName {
+			return prop.Get("type").MustString("string")
+		}
+	}
+	return ""
+}
+
+// 属性值转换为浮点数，json中的数字为json.Number
+func propertyToFloat(value any, def float64) float64 {
+	switch v := value.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return def
+		}
+		return f
+	case float64:
+		return v
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return def
+		}
+		return f
+	}
+	return def
+}
+
+/**
+ * @brief 解析Tiled颜色字符串，格式为"#RRGGBB"或"#AARRGGBB"
+ * @param color 颜色字符串
+ * @return *emath.FColor 解析后的颜色，格式错误返回nil
+ */
+func parseTiledColor(color string) *emath.FColor {
+	hex := strings.TrimPrefix(color, "#")
+	if len(hex) != 6 && len(hex) != 8 {
+		return nil
+	}
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return nil
+	}
+	alpha := uint64(0xFF)
+	if len(hex) == 8 {
+		alpha = (value >> 24) & 0xFF
+	}
+	return &emath.FColor{
+		R: float32((value>>16)&0xFF) / 255.0,
+		G: float32((value>>8)&0xFF) / 255.0,
+		B: float32(value&0xFF) / 255.0,
+		A: float32(alpha) / 255.0,
+	}
+}
+
+// 从地图根节点解析关卡属性
+func (ll *LevelLoader) loadLevelProperties(root *simplejson.Json) {
+	// file类型的属性路径相对于地图文件
+	fileProps := map[string]bool{"music": propertyType(root, "music") == "file"}
+	ll.levelProperties = ll.buildLevelProperties(propertiesToMap(root), fileProps, root.Get("backgroundcolor").MustString(""))
+}
+
+/**
+ * @brief 根据属性映射构建关卡属性，供不同格式的关卡加载器共用。
+ * @param props 属性名 -> 属性值
+ * @param fileProps 属性值为相对于地图文件路径的属性名集合
+ * @param backgroundColor 背景颜色字符串，"#RRGGBB"或"#AARRGGBB"，空表示未设置
+ * @return *LevelProperties 关卡属性
+ */
+func (ll *LevelLoader) buildLevelProperties(props map[string]any, fileProps map[string]bool, backgroundColor string) *LevelProperties {
+	lp := newLevelProperties()
+	lp.Properties = props
+
+	// 背景音乐
+	if music := lp.GetString("music", ""); music != "" {
+		if fileProps["music"] {
+			music = ll.resolvePath(music, ll.mapPath)
+		}
+		lp.Music = music
+	}
+
+	// 重力
+	if _, ok := lp.Properties["gravity"]; ok {
+		lp.Gravity = &mgl32.Vec2{0.0, float32(lp.GetFloat("gravity", 980.0))}
+	}
+
+	// 相机限制范围
+	if boundsString := lp.GetString("camera_bounds", ""); boundsString != "" {
+		boundsJson, err := simplejson.NewJson([]byte(boundsString))
+		if err != nil {
+			slog.Error("parse camera bounds json failed", slog.String("mapPath", ll.mapPath), slog.String("error", err.Error()))
+		} else {
+			lp.CameraBounds = &emath.Rect{
+				Position: mgl32.Vec2{
+					float32(boundsJson.Get("x").MustFloat64(0.0)),
+					float32(boundsJson.Get("y").MustFloat64(0.0)),
+				},
+				Size: mgl32.Vec2{
+					float32(boundsJson.Get("width").MustFloat64(0.0)),
+					float32(boundsJson.Get("height").MustFloat64(0.0)),
+				},
+			}
+		}
+	}
+
+	// 背景颜色
+	if backgroundColor != "" {
+		lp.BackgroundColor = parseTiledColor(backgroundColor)
+		if lp.BackgroundColor == nil {
+			slog.Error("parse background color failed", slog.String("mapPath", ll.mapPath), slog.String("color", backgroundColor))
+		}
+	}
+
+	// 环境光
+	if ambient := lp.GetString("ambient_light", ""); ambient != "" {
+		lp.AmbientLight = parseTiledColor(ambient)
+		if lp.AmbientLight == nil {
+			slog.Error("parse ambient light failed", slog.String("mapPath", ll.mapPath), slog.String("color", ambient))
+		}
+	}
+
+	lp.TimeLimit = max(0.0, lp.GetFloat("time_limit", 0.0))
+	lp.NextLevel = lp.GetString("next_level", "")
+
+	return lp
+}
@@ -0,0 +1,157 @@
+package scene
+
+import (
+	"fmt"
+	"log/slog"
+
+	econtext "sunny_land/src/engine/context"
+	"sunny_land/src/engine/resource"
+	"sunny_land/src/engine/ui"
+	emath "sunny_land/src/engine/utils/math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	// 进度条尺寸
+	loadingBarWidth  = 240.0
+	loadingBarHeight = 12.0
+	// 进度条边框宽度
+	loadingBarBorder = 2.0
+)
+
+/**
+ * @brief 加载场景，显示预加载进度，完成后替换为下一个场景。
+ *
+ * 预加载任务在工作协程中解码资源，本场景每帧在主线程调用任务的Update上传纹理。
+ */
+type LoadingScene struct {
+	// 继承基础场景
+	Scene
+	// 预加载清单
+	manifest *resource.Manifest
+	// 预加载任务，Init时创建
+	task *resource.PreloadTask
+	// 加载完成后进入的场景
+	nextScene IScene
+	// 进度条填充部分
+	barFill *ui.UIPanel
+	// 进度文字，未设置字体时为nil
+	progressLabel *ui.UILabel
+	// 进度文字字体，空表示不显示文字
+	fontId string
+	// 进度文字字体大小
+	fontSize int
+	// 进入下一个场景时的转场，nil表示立即切换
+	transition *Transition
+}
+
+// 确保LoadingScene实现IScene接口
+var _ IScene = (*LoadingScene)(nil)
+
+// 创建加载场景
+func NewLoadingScene(ctx *econtext.Context, sceneManager *SceneManager, manifest *resource.Manifest, nextScene IScene) *LoadingScene {
+	ls := &LoadingScene{
+		manifest:  manifest,
+		nextScene: nextScene,
+	}
+	BuildScene(&ls.Scene, "LoadingScene", ctx, sceneManager)
+	slog.Debug("LoadingScene created")
+	return ls
+}
+
+// 设置进度文字字体，需要在Init之前调用
+func (ls *LoadingScene) SetFont(fontId string, fontSize int) {
+	ls.fontId = fontId
+	ls.fontSize = fontSize
+}
+
+// 设置进入下一个场景时的转场，nil表示立即切换
+func (ls *LoadingScene) SetTransition(transition *Transition) {
+	ls.transition = transition
+}
+
+// 初始化
+func (ls *LoadingScene) Init() {
+	ls.Scene.Init()
+
+	screenSize := ls.GetContext().GetGameState().GetLogicalSize()
+	if !ls.UIManager.Init(screenSize) {
+		slog.Error("ui manager init failed")
+		return
+	}
+
+	// 进度条：边框 + 底色 + 填充
+	barPosition := mgl32.Vec2{
+		(screenSize.X() - loadingBarWidth) / 2.0,
+		(screenSize.Y() - loadingBarHeight) / 2.0,
+	}
+	border := ui.NewUIPanel(
+		barPosition.Sub(mgl32.Vec2{loadingBarBorder, loadingBarBorder}),
+		mgl32.Vec2{loadingBarWidth + loadingBarBorder*2.0, loadingBarHeight + loadingBarBorder*2.0},
+		&emath.FColor{R: 1.0, G: 1.0, B: 1.0, A: 1.0},
+	)
+	background := ui.NewUIPanel(
+		mgl32.Vec2{loadingBarBorder, loadingBarBorder},
+		mgl32.Vec2{loadingBarWidth, loadingBarHeight},
+		&emath.FColor{R: 0.1, G: 0.1, B: 0.1, A: 1.0},
+	)
+	ls.barFill = ui.NewUIPanel(
+		mgl32.Vec2{0.0, 0.0},
+		mgl32.Vec2{0.0, loadingBarHeight},
+		&emath.FColor{R: 0.9, G: 0.7, B: 0.2, A: 1.0},
+	)
+	background.AddChild(ls.barFill)
+	border.AddChild(background)
+	ls.UIManager.AddElement(border)
+
+	if ls.fontId != "" {
+		ls.progressLabel = ui.NewUILabel(ls.GetContext().GetTextRenderer(), "Loading... 0%", ls.fontId, ls.fontSize,
+			emath.FColor{R: 1.0, G: 1.0, B: 1.0, A: 1.0}, mgl32.Vec2{0.0, 0.0})
+		ls.UIManager.AddElement(ls.progressLabel)
+	}
+
+	// 开始预加载，资源记录到下一个场景的作用域，加载场景释放时不会被卸载
+	var scope *resource.ResourceScope
+	if ls.nextScene != nil {
+		scope = ls.nextScene.GetResourceScope()
+	}
+	ls.task = ls.GetContext().GetResourceManager().Preload(ls.manifest, 0, scope)
+	ls.updateProgress()
+	slog.Debug("LoadingScene initialized")
+}
+
+// 更新
+func (ls *LoadingScene) Update(dt float64) {
+	ls.Scene.Update(dt)
+	if ls.task == nil {
+		return
+	}
+
+	done := ls.task.Update()
+	ls.updateProgress()
+	if done && ls.nextScene != nil {
+		if ls.task.GetFailed() > 0 {
+			slog.Warn("some resources failed to preload, they will be loaded on demand", slog.Int("failed", ls.task.GetFailed()))
+		}
+		ls.SceneManager.RequestReplaceSceneWithTransition(ls.nextScene, ls.transition)
+		ls.nextScene = nil
+	}
+}
+
+// 根据任务进度更新进度条与文字
+func (ls *LoadingScene) updateProgress() {
+	progress := ls.task.GetProgress()
+	ls.barFill.SetSize(mgl32.Vec2{loadingBarWidth * progress, loadingBarHeight})
+	if ls.progressLabel == nil {
+		return
+	}
+	ls.progressLabel.SetText(fmt.Sprintf("Loading... %d%%", int(progress*100.0)))
+	// 文字居中显示在进度条上方
+	screenSize := ls.GetContext().GetGameState().GetLogicalSize()
+	labelSize := ls.progressLabel.GetSize()
+	ls.progressLabel.SetPosition(mgl32.Vec2{
+		(screenSize.X() - labelSize.X()) / 2.0,
+		(screenSize.Y()-loadingBarHeight)/2.0 - labelSize.Y() - 8.0,
+	})
+}
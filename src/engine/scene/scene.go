@@ -6,8 +6,10 @@ import (
 
 	econtext "sunny_land/src/engine/context"
 	"sunny_land/src/engine/object"
+	"sunny_land/src/engine/render"
 	"sunny_land/src/engine/resource"
 	"sunny_land/src/engine/ui"
+	emath "sunny_land/src/engine/utils/math"
 )
 
 // 场景接口，负责管理场景中的游戏对象和场景生命周期
@@ -38,6 +40,14 @@ type IScene interface {
 	GetResourceManager() *resource.ResourceManager
 	// 获取上下文
 	GetContext() *econtext.Context
+	// 获取场景的资源作用域，场景被移除后释放
+	GetResourceScope() *resource.ResourceScope
+}
+
+// 文件热重载接口，场景可选实现，文件发生变化时由场景管理器通知当前场景
+type IFileReloadable interface {
+	// 文件发生变化
+	OnFileChanged(string)
 }
 
 // 基础场景
@@ -56,6 +66,12 @@ type Scene struct {
 	GameObjects *list.List
 	// 待添加的游戏对象容器，延迟添加
 	pendingAdditions []*object.GameObject
+	// 资源作用域，记录场景使用过的资源
+	resourceScope *resource.ResourceScope
+	// 场景的相机，按顺序各渲染一次场景(后面的绘制在上层)，为空时只使用上下文中的相机
+	cameras []*render.Camera
+	// 环境光颜色，nil表示不使用光照
+	ambientLight *emath.FColor
 }
 
 // 确保实现了IScene接口
@@ -70,6 +86,7 @@ func BuildScene(s *Scene, sceneName string, ctx *econtext.Context, sceneManager
 	s.initialized = false
 	s.GameObjects = list.New()
 	s.pendingAdditions = make([]*object.GameObject, 0)
+	s.resourceScope = ctx.ResourceManager.NewScope(sceneName)
 }
 
 // 初始化场景
@@ -94,6 +111,11 @@ func (s *Scene) Update(dt float64) {
 		s.ctx.PhysicsEngine.Update(dt)
 		// 更新相机
 		s.ctx.Camera.Update(dt)
+		for _, camera := range s.cameras {
+			if camera != s.ctx.Camera {
+				camera.Update(dt)
+			}
+		}
 	}
 
 	// 更新所有游戏对象，并删除需要移除的对象
@@ -133,14 +155,85 @@ func (s *Scene) Render() {
 		return
 	}
 
-	// 渲染所有游戏对象
+	if len(s.cameras) == 0 {
+		s.renderGameObjects(s.ctx.Camera)
+	} else {
+		// 每个相机渲染一次，渲染期间上下文中的相机替换为当前相机，组件通过上下文获取相机
+		mainCamera := s.ctx.Camera
+		for _, camera := range s.cameras {
+			s.ctx.Camera = camera
+			s.ctx.Renderer.BeginViewport(camera)
+			s.renderGameObjects(camera)
+			s.ctx.Renderer.EndViewport()
+		}
+		s.ctx.Camera = mainCamera
+	}
+
+	// 渲染UI管理器
+	s.UIManager.Render(s.ctx)
+}
+
+// 渲染所有游戏对象，绘制命令按图层排序后批量绘制，使用光照时最后合成暗度层(在UI之前，UI不受光照影响)
+func (s *Scene) renderGameObjects(camera *render.Camera) {
+	if s.ambientLight != nil {
+		s.ctx.Renderer.BeginLighting()
+	}
+	s.ctx.Renderer.BeginQueue()
 	for e := s.GameObjects.Front(); e != nil; e = e.Next() {
 		gt := e.Value.(*object.GameObject)
 		gt.Render(s.ctx)
 	}
+	s.ctx.Renderer.FlushQueue()
+	if s.ambientLight != nil {
+		s.ctx.Renderer.EndLighting(camera, *s.ambientLight)
+	}
+}
 
-	// 渲染UI管理器
-	s.UIManager.Render(s.ctx)
+/**
+ * @brief 设置环境光，用于洞穴或夜晚关卡，光照只作用于本场景的游戏对象
+ * @param color 环境光颜色，没有光源照亮的部分以此颜色调制，nil表示不使用光照
+ */
+func (s *Scene) SetAmbientLight(color *emath.FColor) {
+	s.ambientLight = color
+}
+
+// 获取环境光颜色，nil表示不使用光照
+func (s *Scene) GetAmbientLight() *emath.FColor {
+	return s.ambientLight
+}
+
+/**
+ * @brief 添加相机，场景按添加顺序为每个相机渲染一次，用于分屏或画中画(例如小地图)
+ *
+ * 需要与其他相机一起显示时，上下文中的相机也要添加进来。
+ * @param camera 相机，需要先设置视口区域
+ */
+func (s *Scene) AddCamera(camera *render.Camera) {
+	if camera == nil {
+		slog.Warn("add nil camera", slog.String("sceneName", s.sceneName))
+		return
+	}
+	for _, c := range s.cameras {
+		if c == camera {
+			return
+		}
+	}
+	s.cameras = append(s.cameras, camera)
+}
+
+// 移除相机，所有相机移除后只渲染上下文中的相机
+func (s *Scene) RemoveCamera(camera *render.Camera) {
+	for i, c := range s.cameras {
+		if c == camera {
+			s.cameras = append(s.cameras[:i], s.cameras[i+1:]...)
+			return
+		}
+	}
+}
+
+// 获取场景的相机
+func (s *Scene) GetCameras() []*render.Camera {
+	return s.cameras
 }
 
 // 处理输入事件
@@ -179,6 +272,8 @@ func (s *Scene) Clean() {
 		return
 	}
 	s.initialized = false
+	s.cameras = nil
+	s.ambientLight = nil
 
 	// 清理所有游戏对象
 	for e := s.GameObjects.Front(); e != nil; e = e.Next() {
@@ -273,6 +368,11 @@ func (s *Scene) FindGameObjectByName(name string) *object.GameObject {
 	return nil
 }
 
+// 获取场景的资源作用域
+func (s *Scene) GetResourceScope() *resource.ResourceScope {
+	return s.resourceScope
+}
+
 // 获取资源管理器
 func (s *Scene) GetResourceManager() *resource.ResourceManager {
 	return s.ctx.ResourceManager
@@ -1,9 +1,11 @@
 package scene
 
 import (
+	"context"
 	"log/slog"
 
 	econtext "sunny_land/src/engine/context"
+	"sunny_land/src/engine/resource"
 )
 
 // 待处理操作
@@ -20,6 +22,14 @@ const (
 	PendingActionReplace
 )
 
+// 转场覆盖期间提交的切换场景请求，切换场景后依次执行
+type deferredRequest struct {
+	// 切换场景操作
+	action PendingAction
+	// 操作的场景，出栈时为nil
+	scene IScene
+}
+
 // 场景管理器
 type SceneManager struct {
 	// 引擎上下文
@@ -30,6 +40,12 @@ type SceneManager struct {
 	pendingAction PendingAction
 	// 待处理场景
 	pendingScene IScene
+	// 待释放的资源作用域，场景移除后的下一帧释放，新场景已经获取了共用的资源
+	pendingReleases []*resource.ResourceScope
+	// 进行中的转场，nil表示没有
+	transition *activeTransition
+	// 转场覆盖期间提交的请求，转场切换场景后每帧执行一个
+	deferredRequests []deferredRequest
 }
 
 // 创建场景管理器
@@ -43,12 +59,40 @@ func NewSceneManager(context *econtext.Context) *SceneManager {
 	}
 }
 
+// 激活场景的资源作用域，之后获取的资源记录到该场景，返回恢复函数
+func (sm *SceneManager) activateScope(scene IScene) func() {
+	rm := sm.context.ResourceManager
+	prev := rm.SetCurrentScope(scene.GetResourceScope())
+	return func() {
+		rm.SetCurrentScope(prev)
+	}
+}
+
+// 释放待释放的资源作用域，引用计数归零的资源被卸载
+func (sm *SceneManager) releasePendingScopes() {
+	if len(sm.pendingReleases) == 0 {
+		return
+	}
+	rm := sm.context.ResourceManager
+	for _, scope := range sm.pendingReleases {
+		rm.ReleaseScope(scope)
+	}
+	sm.pendingReleases = sm.pendingReleases[:0]
+	if slog.Default().Enabled(context.Background(), slog.LevelDebug) {
+		slog.Debug("resident assets after scene change\n" + rm.Report())
+	}
+}
+
 // 清理场景管理器
 func (sm *SceneManager) Cleanup() {
 	slog.Debug("cleanup scene manager")
 	for _, scene := range sm.sceneStack {
 		scene.Clean()
+		sm.pendingReleases = append(sm.pendingReleases, scene.GetResourceScope())
 	}
+	sm.releasePendingScopes()
+	sm.transition = nil
+	sm.deferredRequests = nil
 }
 
 // 获取当前场景
@@ -59,53 +103,170 @@ func (sm *SceneManager) GetCurrentScene() IScene {
 	return sm.sceneStack[len(sm.sceneStack)-1]
 }
 
+// 通知当前(栈顶)场景文件发生变化，场景未实现IFileReloadable时忽略
+func (sm *SceneManager) NotifyFileChanged(path string) {
+	if reloadable, ok := sm.GetCurrentScene().(IFileReloadable); ok {
+		reloadable.OnFileChanged(path)
+	}
+}
+
 // 更新
 func (sm *SceneManager) Update(dt float64) {
 	// 只更新当前(栈顶)场景
 	currentScene := sm.GetCurrentScene()
 	if currentScene != nil {
+		restore := sm.activateScope(currentScene)
 		currentScene.Update(dt)
+		restore()
 	}
+	// 转场覆盖完成时提交切换场景操作
+	sm.updateTransition(dt)
 	// 执行可能的切换场景操作
 	sm.processPendingActions()
 }
 
+// 更新转场，前一半结束时提交切换场景操作，后一半结束时结束转场
+func (sm *SceneManager) updateTransition(dt float64) {
+	if sm.transition == nil {
+		return
+	}
+	transition := sm.transition
+	transition.elapsed += dt
+	if transition.elapsed < transition.halfDuration() {
+		return
+	}
+	if transition.switched {
+		sm.transition = nil
+		slog.Debug("scene transition finished")
+		return
+	}
+	transition.switched = true
+	transition.elapsed = 0.0
+	sm.pendingAction = transition.action
+	sm.pendingScene = transition.scene
+}
+
 // 渲染
 func (sm *SceneManager) Render() {
 	// 渲染时需要叠加渲染所有场景，而不只是栈顶
 	for _, scene := range sm.sceneStack {
+		restore := sm.activateScope(scene)
 		scene.Render()
+		restore()
+	}
+	// 转场覆盖层绘制在所有场景之上
+	if sm.transition != nil {
+		sm.transition.render(sm.context.Renderer, sm.context.Camera, sm.context.GetGameState().GetLogicalSize())
 	}
 }
 
 // 处理事件
 func (sm *SceneManager) HandleInput() {
+	// 转场期间不处理输入
+	if sm.transition != nil {
+		return
+	}
 	// 只处理当前(栈顶)场景的事件
 	currentScene := sm.GetCurrentScene()
 	if currentScene != nil {
+		restore := sm.activateScope(currentScene)
 		currentScene.HandleInput()
+		restore()
 	}
 }
 
 // 请求弹出当前场景
 func (sm *SceneManager) RequestPopScene() {
-	sm.pendingAction = PendingActionPop
+	sm.setPendingAction(PendingActionPop, nil)
 }
 
 // 请求替换当前场景
 func (sm *SceneManager) RequestReplaceScene(scene IScene) {
-	sm.pendingAction = PendingActionReplace
-	sm.pendingScene = scene
+	sm.setPendingAction(PendingActionReplace, scene)
 }
 
 // 请求压栈场景
 func (sm *SceneManager) RequestPushScene(scene IScene) {
-	sm.pendingAction = PendingActionPush
+	sm.setPendingAction(PendingActionPush, scene)
+}
+
+/**
+ * @brief 设置待处理操作
+ *
+ * 转场正在覆盖旧场景时，操作推迟到转场切换场景之后执行，
+ * 否则会在覆盖完成时被转场的操作覆盖，或者在覆盖期间改变转场将要操作的场景栈。
+ * @param action 切换场景操作
+ * @param scene 操作的场景，出栈时为nil
+ */
+func (sm *SceneManager) setPendingAction(action PendingAction, scene IScene) {
+	if sm.transition != nil && !sm.transition.switched {
+		sm.deferredRequests = append(sm.deferredRequests, deferredRequest{action: action, scene: scene})
+		slog.Info("scene transition in progress, request deferred until scene switched", slog.Int("action", int(action)))
+		return
+	}
+	sm.pendingAction = action
 	sm.pendingScene = scene
 }
 
+// 请求带转场地弹出当前场景
+func (sm *SceneManager) RequestPopSceneWithTransition(transition *Transition) {
+	sm.requestTransition(PendingActionPop, nil, transition)
+}
+
+// 请求带转场地替换当前场景
+func (sm *SceneManager) RequestReplaceSceneWithTransition(scene IScene, transition *Transition) {
+	sm.requestTransition(PendingActionReplace, scene, transition)
+}
+
+// 请求带转场地压栈场景
+func (sm *SceneManager) RequestPushSceneWithTransition(scene IScene, transition *Transition) {
+	sm.requestTransition(PendingActionPush, scene, transition)
+}
+
+// 是否正在转场
+func (sm *SceneManager) IsTransitioning() bool {
+	return sm.transition != nil
+}
+
+/**
+ * @brief 开始转场，覆盖完成时执行切换场景操作
+ *
+ * 正在覆盖旧场景时拒绝新的转场请求并记录警告，不带转场的请求推迟到切换场景之后执行；
+ * 正在显露新场景时从当前覆盖程度开始新的转场。
+ * @param action 切换场景操作
+ * @param scene 操作的场景
+ * @param transition 转场配置，nil或时长不大于0时立即切换
+ */
+func (sm *SceneManager) requestTransition(action PendingAction, scene IScene, transition *Transition) {
+	if transition == nil || transition.Duration <= 0.0 {
+		sm.setPendingAction(action, scene)
+		return
+	}
+	next := &activeTransition{config: *transition, action: action, scene: scene}
+	if sm.transition != nil {
+		if !sm.transition.switched {
+			slog.Warn("scene transition already in progress, request ignored")
+			return
+		}
+		next.elapsed = float64(sm.transition.coverage()) * next.halfDuration()
+	}
+	sm.transition = next
+	slog.Debug("scene transition started", slog.Int("type", int(transition.Type)), slog.Float64("duration", transition.Duration))
+}
+
 // 处理待处理操作
 func (sm *SceneManager) processPendingActions() {
+	// 释放上一帧移除的场景的资源
+	sm.releasePendingScopes()
+
+	// 转场已经切换场景，依次执行覆盖期间推迟的请求
+	if sm.pendingAction == PendingActionNone && len(sm.deferredRequests) > 0 && (sm.transition == nil || sm.transition.switched) {
+		request := sm.deferredRequests[0]
+		sm.deferredRequests = sm.deferredRequests[1:]
+		sm.pendingAction = request.action
+		sm.pendingScene = request.scene
+	}
+
 	if sm.pendingAction == PendingActionNone {
 		return
 	}
@@ -133,6 +294,7 @@ func (sm *SceneManager) popScene() {
 	currentScene := sm.GetCurrentScene()
 	if currentScene != nil {
 		currentScene.Clean()
+		sm.pendingReleases = append(sm.pendingReleases, currentScene.GetResourceScope())
 	}
 	sm.sceneStack[len(sm.sceneStack)-1] = nil
 	sm.sceneStack = sm.sceneStack[:len(sm.sceneStack)-1]
@@ -150,13 +312,16 @@ func (sm *SceneManager) replaceScene(scene IScene) {
 	// 清理并移除场景栈中所有场景
 	for i, s := range sm.sceneStack {
 		s.Clean()
+		sm.pendingReleases = append(sm.pendingReleases, s.GetResourceScope())
 		sm.sceneStack[i] = nil
 	}
 	sm.sceneStack = sm.sceneStack[:0]
 
 	// 初始化新场景
 	if !scene.IsInitialized() {
+		restore := sm.activateScope(scene)
 		scene.Init()
+		restore()
 	}
 
 	// 将新场景压入栈顶
@@ -172,7 +337,9 @@ func (sm *SceneManager) pushScene(scene IScene) {
 	}
 
 	if !scene.IsInitialized() {
+		restore := sm.activateScope(scene)
 		scene.Init()
+		restore()
 	}
 
 	sm.sceneStack = append(sm.sceneStack, scene)
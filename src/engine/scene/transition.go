@@ -0,0 +1,145 @@
+package scene
+
+import (
+	"sunny_land/src/engine/render"
+	emath "sunny_land/src/engine/utils/math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// 转场效果类型
+type TransitionType int
+
+const (
+	// 淡出到颜色，切换场景后再淡入
+	TransitionFade TransitionType = iota
+	// 颜色从一侧擦入覆盖画面，切换场景后向同一方向擦出
+	TransitionWipe
+	// 圆形开口收缩到玩家(相机目标)处，切换场景后从新场景的玩家处张开
+	TransitionIris
+)
+
+// 擦除方向
+type WipeDirection int
+
+const (
+	// 从左向右
+	WipeRight WipeDirection = iota
+	// 从右向左
+	WipeLeft
+	// 从上向下
+	WipeDown
+	// 从下向上
+	WipeUp
+)
+
+/**
+ * @brief 转场配置，随切换场景的请求一起提交。
+ *
+ * 前一半时长覆盖旧场景，覆盖完成时切换场景，后一半时长显露新场景，转场期间不处理输入。
+ */
+type Transition struct {
+	// 效果类型
+	Type TransitionType
+	// 总时长(秒)
+	Duration float64
+	// 覆盖颜色
+	Color emath.FColor
+	// 擦除方向，只用于TransitionWipe
+	Direction WipeDirection
+}
+
+// 创建淡入淡出转场
+func NewFadeTransition(duration float64, color emath.FColor) *Transition {
+	return &Transition{Type: TransitionFade, Duration: duration, Color: color}
+}
+
+// 创建擦除转场
+func NewWipeTransition(duration float64, color emath.FColor, direction WipeDirection) *Transition {
+	return &Transition{Type: TransitionWipe, Duration: duration, Color: color, Direction: direction}
+}
+
+// 创建以玩家为中心的圆形转场
+func NewIrisTransition(duration float64, color emath.FColor) *Transition {
+	return &Transition{Type: TransitionIris, Duration: duration, Color: color}
+}
+
+// 进行中的转场
+type activeTransition struct {
+	// 转场配置
+	config Transition
+	// 是否已经切换场景(进入后一半)
+	switched bool
+	// 当前一半已经过的时间
+	elapsed float64
+	// 覆盖完成时执行的操作
+	action PendingAction
+	// 操作的场景，出栈时为nil
+	scene IScene
+}
+
+// 获取一半时长
+func (at *activeTransition) halfDuration() float64 {
+	return at.config.Duration * 0.5
+}
+
+// 获取覆盖程度，0表示完全显露，1表示完全覆盖
+func (at *activeTransition) coverage() float32 {
+	t := float32(min(at.elapsed/at.halfDuration(), 1.0))
+	if at.switched {
+		return 1.0 - t
+	}
+	return t
+}
+
+/**
+ * @brief 绘制转场覆盖层
+ * @param renderer 渲染器
+ * @param camera 相机，圆形转场以相机目标为中心，可以为nil
+ * @param screenSize 屏幕(逻辑)尺寸
+ */
+func (at *activeTransition) render(renderer *render.Renderer, camera *render.Camera, screenSize mgl32.Vec2) {
+	coverage := emath.EaseInOutQuad(at.coverage())
+	if coverage <= 0.0 {
+		return
+	}
+	screen := emath.Rect{Size: screenSize}
+	color := at.config.Color
+
+	switch at.config.Type {
+	case TransitionFade:
+		color.A *= coverage
+		renderer.DrawUIFilledRect(screen, color)
+	case TransitionWipe:
+		renderer.DrawUIFilledRect(at.wipeRect(screenSize, coverage), color)
+	case TransitionIris:
+		center := screenSize.Mul(0.5)
+		if camera != nil && camera.GetTargetTC() != nil {
+			center = camera.WorldToScreen(camera.GetTargetTC().GetPosition()).Add(camera.GetViewportRect().Position)
+		}
+		// 开口半径从中心到屏幕最远角的距离收缩到0
+		farthest := mgl32.Vec2{max(center.X(), screenSize.X()-center.X()), max(center.Y(), screenSize.Y()-center.Y())}
+		renderer.DrawUIIris(screen, center, farthest.Len()*(1.0-coverage), color)
+	}
+}
+
+// 计算擦除覆盖的矩形，切换前从起始边覆盖到coverage处，切换后从coverage处覆盖到结束边
+func (at *activeTransition) wipeRect(screenSize mgl32.Vec2, coverage float32) emath.Rect {
+	horizontal := at.config.Direction == WipeRight || at.config.Direction == WipeLeft
+	length := screenSize.Y()
+	if horizontal {
+		length = screenSize.X()
+	}
+	start, end := float32(0.0), coverage*length
+	if at.switched {
+		start, end = (1.0-coverage)*length, length
+	}
+	// 反方向时镜像
+	if at.config.Direction == WipeLeft || at.config.Direction == WipeUp {
+		start, end = length-end, length-start
+	}
+	if horizontal {
+		return emath.Rect{Position: mgl32.Vec2{start, 0.0}, Size: mgl32.Vec2{end - start, screenSize.Y()}}
+	}
+	return emath.Rect{Position: mgl32.Vec2{0.0, start}, Size: mgl32.Vec2{screenSize.X(), end - start}}
+}
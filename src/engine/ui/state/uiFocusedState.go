@@ -0,0 +1,48 @@
+package state
+
+import econtext "sunny_land/src/engine/context"
+
+/**
+ * @brief 焦点状态
+ *
+ * 当UI元素通过键盘、手柄导航获得焦点(且鼠标不在其上)时，会切换到该状态，显示与悬停相同的精灵图。
+ */
+type UIFocusedState struct {
+	// 继承基础UI状态
+	UIState
+}
+
+// 确保UIFocusedState实现IUIState接口
+var _ IUIState = (*UIFocusedState)(nil)
+
+// 创建焦点状态实例
+func NewUIFocusedState(owner IUIInteractive) *UIFocusedState {
+	return &UIFocusedState{
+		UIState: UIState{
+			owner: owner,
+		},
+	}
+}
+
+// 进入状态
+func (f *UIFocusedState) Enter() {
+	f.owner.SetSprite("hover")
+}
+
+// 处理输入
+func (f *UIFocusedState) HandleInput(ctx *econtext.Context) IUIState {
+	inputManager := ctx.GetInputManager()
+	// 如果失去焦点，则切换到正常状态
+	if !f.owner.IsFocused() {
+		return NewUINormalState(f.owner)
+	}
+	// 如果鼠标移到UI元素内，则切换到悬停状态
+	if f.owner.IsPointInside(inputManager.GetLogicalMousePosition()) {
+		return NewUIHoverState(f.owner)
+	}
+	// 如果确认键按下，则返回按下状态
+	if inputManager.IsActionPressed("ui_confirm") {
+		return NewUIConfirmPressedState(f.owner)
+	}
+	return nil
+}
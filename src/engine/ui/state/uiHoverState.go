@@ -41,5 +41,9 @@ func (h *UIHoverState) HandleInput(ctx *econtext.Context) IUIState {
 	if inputManager.IsActionPressed("MouseLeftClick") {
 		return NewUIPressedState(h.owner)
 	}
+	// 如果同时获得焦点，确认键同样可以按下
+	if h.owner.IsFocused() && inputManager.IsActionPressed("ui_confirm") {
+		return NewUIConfirmPressedState(h.owner)
+	}
 	return nil
 }
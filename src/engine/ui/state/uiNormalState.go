@@ -40,5 +40,9 @@ func (n *UINormalState) HandleInput(ctx *econtext.Context) IUIState {
 		n.owner.PlaySound("hover")
 		return NewUIHoverState(n.owner)
 	}
+	// 如果通过键盘、手柄获得焦点，则切换到焦点状态
+	if n.owner.IsFocused() {
+		return NewUIFocusedState(n.owner)
+	}
 	return nil
 }
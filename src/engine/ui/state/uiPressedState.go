@@ -5,11 +5,13 @@ import econtext "sunny_land/src/engine/context"
 /**
  * @brief 按下状态
  *
- * 当鼠标按下UI元素时，会切换到该状态。
+ * 当鼠标按下UI元素，或者UI元素获得焦点时按下确认键，会切换到该状态。
  */
 type UIPressedState struct {
 	// 继承基础UI状态
 	UIState
+	// 是否由确认键按下，否则为鼠标按下
+	byConfirm bool
 }
 
 // 确保UIPressedState实现IUIState接口
@@ -24,6 +26,16 @@ func NewUIPressedState(owner IUIInteractive) *UIPressedState {
 	}
 }
 
+// 创建由确认键按下的按下状态实例，松开确认键时触发点击
+func NewUIConfirmPressedState(owner IUIInteractive) *UIPressedState {
+	return &UIPressedState{
+		UIState: UIState{
+			owner: owner,
+		},
+		byConfirm: true,
+	}
+}
+
 // 进入状态
 func (p *UIPressedState) Enter() {
 	p.owner.SetSprite("pressed")
@@ -33,6 +45,19 @@ func (p *UIPressedState) Enter() {
 // 处理输入
 func (p *UIPressedState) HandleInput(ctx *econtext.Context) IUIState {
 	inputManager := ctx.GetInputManager()
+	if p.byConfirm {
+		// 失去焦点则取消按下
+		if !p.owner.IsFocused() {
+			return NewUINormalState(p.owner)
+		}
+		// 如果确认键松开，则返回焦点状态
+		if inputManager.IsActionReleased("ui_confirm") {
+			p.owner.Clicked()
+			return NewUIFocusedState(p.owner)
+		}
+		return nil
+	}
+
 	mousePos := inputManager.GetLogicalMousePosition()
 	// 如果鼠标不在UI元素内，则切换到正常状态
 	if !p.owner.IsPointInside(mousePos) {
@@ -1,7 +1,10 @@
 package state
 
 import (
+	"container/list"
+
 	econtext "sunny_land/src/engine/context"
+	emath "sunny_land/src/engine/utils/math"
 
 	"github.com/go-gl/mathgl/mgl32"
 )
@@ -22,10 +25,24 @@ type IUIElement interface {
 	GetParent() IUIElement
 	// 添加子元素
 	AddChild(child IUIElement)
+	// 获取子元素列表
+	GetChildren() *list.List
 	// 获取(计算)元素在屏幕上位置, 相对于屏幕左上角
 	GetScreenPosition() mgl32.Vec2
 	// 检查给定点是否在元素的边界内
 	IsPointInside(mgl32.Vec2) bool
+	// 获取元素大小
+	GetSize() mgl32.Vec2
+	// 获取元素位置, 相对于父元素
+	GetPosition() mgl32.Vec2
+	// 设置元素位置, 相对于父元素
+	SetPosition(mgl32.Vec2)
+	// 是否可见
+	IsVisible() bool
+	// 获取外边距
+	GetMargin() emath.Insets
+	// 布局，先布局子元素，容器元素重写此方法以排列子元素
+	Layout()
 }
 
 // 可交互UI元素的抽象
@@ -38,6 +55,12 @@ type IUIInteractive interface {
 	SetSprite(string)
 	// 播放音效
 	PlaySound(string)
+	// 获取是否可交互
+	GetInteractive() bool
+	// 是否获得焦点(键盘、手柄导航)
+	IsFocused() bool
+	// 设置是否获得焦点，由UIManager调用
+	SetFocused(bool)
 }
 
 // UI状态的抽象
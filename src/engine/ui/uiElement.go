@@ -10,10 +10,35 @@ import (
 	"github.com/go-gl/mathgl/mgl32"
 )
 
-// UI元素基础实现
+// 常用的锚点/轴心，(0,0)为左上角，(1,1)为右下角
+var (
+	AnchorTopLeft     = mgl32.Vec2{0.0, 0.0}
+	AnchorTop         = mgl32.Vec2{0.5, 0.0}
+	AnchorTopRight    = mgl32.Vec2{1.0, 0.0}
+	AnchorLeft        = mgl32.Vec2{0.0, 0.5}
+	AnchorCenter      = mgl32.Vec2{0.5, 0.5}
+	AnchorRight       = mgl32.Vec2{1.0, 0.5}
+	AnchorBottomLeft  = mgl32.Vec2{0.0, 1.0}
+	AnchorBottom      = mgl32.Vec2{0.5, 1.0}
+	AnchorBottomRight = mgl32.Vec2{1.0, 1.0}
+)
+
+/**
+ * @brief UI元素基础实现
+ *
+ * 元素在屏幕上的位置 = 父元素位置 + 锚点 * 父元素大小 - 轴心 * 自身大小 + 局部位置，
+ * 锚点与轴心为0~1的比例，默认都为左上角(0,0)，此时局部位置就是相对于父元素左上角的位置。
+ * 例如锚点与轴心都为(0.5,0.5)时元素在父元素中居中，父元素大小变化后仍然居中。
+ */
 type UIElement struct {
-	// 相对于父元素的局部位置
+	// 相对于父元素的局部位置，设置了锚点时为相对于锚点的偏移
 	position mgl32.Vec2
+	// 锚点，父元素中的比例位置
+	anchor mgl32.Vec2
+	// 轴心，自身的比例位置，与锚点对齐
+	pivot mgl32.Vec2
+	// 外边距，由堆叠容器使用
+	margin emath.Insets
 	// 元素大小
 	size mgl32.Vec2
 	// 元素当前是否可见
@@ -84,6 +109,13 @@ func (e *UIElement) Update(dt float64, ctx *econtext.Context) {
 	}
 }
 
+// 布局，先布局子元素，容器元素重写此方法以排列子元素
+func (e *UIElement) Layout() {
+	for child := e.children.Front(); child != nil; child = child.Next() {
+		child.Value.(state.IUIElement).Layout()
+	}
+}
+
 // 渲染
 func (e *UIElement) Render(ctx *econtext.Context) {
 	if !e.visible {
@@ -183,11 +215,44 @@ func (e *UIElement) SetPosition(position mgl32.Vec2) {
 	e.position = position
 }
 
+/**
+ * @brief 设置锚点与轴心，局部位置变为相对于锚点的偏移
+ * @param anchor 锚点，父元素中的比例位置，例如AnchorCenter
+ * @param pivot 轴心，自身的比例位置
+ * @param offset 相对于锚点的偏移
+ */
+func (e *UIElement) SetAnchor(anchor, pivot, offset mgl32.Vec2) {
+	e.anchor = anchor
+	e.pivot = pivot
+	e.position = offset
+}
+
+// 获取锚点
+func (e *UIElement) GetAnchor() mgl32.Vec2 {
+	return e.anchor
+}
+
+// 获取轴心
+func (e *UIElement) GetPivot() mgl32.Vec2 {
+	return e.pivot
+}
+
+// 设置外边距
+func (e *UIElement) SetMargin(margin emath.Insets) {
+	e.margin = margin
+}
+
+// 获取外边距
+func (e *UIElement) GetMargin() emath.Insets {
+	return e.margin
+}
+
 // 获取(计算)元素在屏幕上位置, 相对于屏幕左上角
 func (e *UIElement) GetScreenPosition() mgl32.Vec2 {
-	// 递归计算父元素的屏幕位置
+	// 递归计算父元素的屏幕位置，再加上锚点与轴心的偏移
 	if e.parent != nil {
-		return e.parent.GetScreenPosition().Add(e.position)
+		anchorOffset := emath.Mgl32Vec2MulElem(e.parent.GetSize(), e.anchor).Sub(emath.Mgl32Vec2MulElem(e.size, e.pivot))
+		return e.parent.GetScreenPosition().Add(anchorOffset).Add(e.position)
 	}
 	// 根元素的位置已经是相对屏幕的绝对位置
 	return e.position
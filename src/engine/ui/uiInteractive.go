@@ -31,6 +31,8 @@ type UIInteractive struct {
 	currentSprite *render.Sprite
 	// 是否可以交互
 	interactive bool
+	// 是否获得焦点(键盘、手柄导航)
+	focused bool
 }
 
 // 确保UIInteractive实现了IUIInteractive接口
@@ -109,6 +111,16 @@ func (uii *UIInteractive) GetInteractive() bool {
 	return uii.interactive
 }
 
+// 是否获得焦点
+func (uii *UIInteractive) IsFocused() bool {
+	return uii.focused
+}
+
+// 设置是否获得焦点，由UIManager调用
+func (uii *UIInteractive) SetFocused(focused bool) {
+	uii.focused = focused
+}
+
 // 处理输入事件
 func (uii *UIInteractive) HandleInput(ctx *econtext.Context) bool {
 	if uii.UIElement.HandleInput(ctx) {
@@ -2,8 +2,11 @@ package ui
 
 import (
 	"log/slog"
+	"math"
+
 	econtext "sunny_land/src/engine/context"
 	"sunny_land/src/engine/ui/state"
+	emath "sunny_land/src/engine/utils/math"
 
 	"github.com/go-gl/mathgl/mgl32"
 )
@@ -13,17 +16,47 @@ import (
  *
  * 负责UI元素的生命周期管理（通过根元素）、渲染调用和输入事件分发。
  * 每个需要UI的场景（如菜单、游戏HUD）应该拥有一个UIManager实例。
+ *
+ * 同时管理键盘、手柄的焦点导航：
+ * - ui_up/ui_down/ui_left/ui_right 在可见且可交互的元素之间按方向移动焦点，没有焦点时聚焦第一个元素
+ * - ui_confirm 由获得焦点的元素处理(按下、松开后点击)
+ * - ui_cancel 调用取消回调，例如关闭菜单
+ * 鼠标移动时焦点跟随鼠标下的元素，焦点指示框只在使用导航后显示。
  */
 type UIManager struct {
 	// 一个UIPanel作为根节点(UI元素)
 	rootElement *UIPanel
+	// 当前获得焦点的元素
+	focused state.IUIInteractive
+	// 是否显示焦点指示框，使用导航后显示，鼠标移动后隐藏
+	showFocusIndicator bool
+	// 焦点指示框颜色
+	focusIndicatorColor emath.FColor
+	// 上一次处理输入时的鼠标位置
+	lastMousePosition mgl32.Vec2
+	// 取消回调
+	cancelCallback func()
+	// 计时，用于焦点指示框闪烁
+	elapsed float64
 }
 
+const (
+	// 焦点指示框与元素之间的间距
+	focusIndicatorPadding = 2.0
+	// 焦点指示框的宽度
+	focusIndicatorThickness = 2.0
+	// 焦点指示框闪烁频率(次/秒)
+	focusIndicatorPulseSpeed = 1.5
+	// 导航时垂直于方向的偏移的权重，越大越倾向于选择同一行/列的元素
+	focusCrossWeight = 2.0
+)
+
 // 创建UIManager实例
 func NewUIManager() *UIManager {
 	slog.Debug("create ui manager")
 	return &UIManager{
-		rootElement: NewUIPanel(mgl32.Vec2{}, mgl32.Vec2{}, nil),
+		rootElement:         NewUIPanel(mgl32.Vec2{}, mgl32.Vec2{}, nil),
+		focusIndicatorColor: emath.FColor{R: 1.0, G: 0.9, B: 0.4, A: 1.0},
 	}
 }
 
@@ -47,6 +80,7 @@ func (um *UIManager) GetRootElement() *UIPanel {
 // 清除所有UI元素，通常用于重置UI状态
 func (um *UIManager) ClearElements() {
 	um.rootElement.RemoveAllChildren()
+	um.SetFocus(nil)
 }
 
 // 处理输入事件，如果事件被处理则返回true。
@@ -54,15 +88,177 @@ func (um *UIManager) HandleInput(ctx *econtext.Context) bool {
 	if !um.rootElement.IsVisible() {
 		return false
 	}
+	// 先处理焦点导航与取消
+	if um.handleFocusInput(ctx) {
+		return true
+	}
 	// 从根元素开始向下分发事件
 	return um.rootElement.HandleInput(ctx)
 }
 
+// 处理焦点导航与取消，如果事件被处理则返回true
+func (um *UIManager) handleFocusInput(ctx *econtext.Context) bool {
+	inputManager := ctx.GetInputManager()
+	focusables := um.collectFocusables(um.rootElement, nil)
+
+	// 获得焦点的元素被移除、隐藏或者不可交互时失去焦点
+	if um.focused != nil && !containsFocusable(focusables, um.focused) {
+		um.SetFocus(nil)
+	}
+
+	// 鼠标移动时焦点跟随鼠标下的元素，并隐藏焦点指示框
+	mousePos := inputManager.GetLogicalMousePosition()
+	if mousePos != um.lastMousePosition {
+		um.lastMousePosition = mousePos
+		um.showFocusIndicator = false
+		var hovered state.IUIInteractive
+		for _, element := range focusables {
+			if element.IsPointInside(mousePos) {
+				hovered = element
+				break
+			}
+		}
+		um.SetFocus(hovered)
+	}
+
+	if inputManager.IsActionPressed("ui_cancel") && um.cancelCallback != nil {
+		um.cancelCallback()
+		return true
+	}
+
+	if len(focusables) == 0 {
+		return false
+	}
+	direction := mgl32.Vec2{}
+	switch {
+	case inputManager.IsActionPressed("ui_up"):
+		direction = mgl32.Vec2{0.0, -1.0}
+	case inputManager.IsActionPressed("ui_down"):
+		direction = mgl32.Vec2{0.0, 1.0}
+	case inputManager.IsActionPressed("ui_left"):
+		direction = mgl32.Vec2{-1.0, 0.0}
+	case inputManager.IsActionPressed("ui_right"):
+		direction = mgl32.Vec2{1.0, 0.0}
+	}
+	// 没有焦点时，方向键与确认键都只聚焦第一个元素
+	if um.focused == nil {
+		if direction == (mgl32.Vec2{}) && !inputManager.IsActionPressed("ui_confirm") {
+			return false
+		}
+		um.SetFocus(focusables[0])
+		um.showFocusIndicator = true
+		return true
+	}
+	if direction == (mgl32.Vec2{}) {
+		return false
+	}
+	um.showFocusIndicator = true
+	if next := findFocusNeighbor(um.focused, focusables, direction); next != nil {
+		um.SetFocus(next)
+		next.PlaySound("hover")
+	}
+	return true
+}
+
+// 按树的顺序收集可见且可交互的元素，隐藏元素的子元素同样不可见
+func (um *UIManager) collectFocusables(element state.IUIElement, result []state.IUIInteractive) []state.IUIInteractive {
+	for child := element.GetChildren().Front(); child != nil; child = child.Next() {
+		uiElement := child.Value.(state.IUIElement)
+		if !uiElement.IsVisible() || uiElement.IsNeedRemove() {
+			continue
+		}
+		if interactive, ok := uiElement.(state.IUIInteractive); ok && interactive.GetInteractive() {
+			result = append(result, interactive)
+		}
+		result = um.collectFocusables(uiElement, result)
+	}
+	return result
+}
+
+// 元素列表中是否包含指定元素
+func containsFocusable(focusables []state.IUIInteractive, element state.IUIInteractive) bool {
+	for _, focusable := range focusables {
+		if focusable == element {
+			return true
+		}
+	}
+	return false
+}
+
+// 获取元素中心的屏幕坐标
+func elementCenter(element state.IUIElement) mgl32.Vec2 {
+	return element.GetScreenPosition().Add(element.GetSize().Mul(0.5))
+}
+
+/**
+ * @brief 查找指定方向上最近的元素
+ *
+ * 只考虑中心在该方向上的元素，距离为沿方向的距离加上垂直于方向的偏移乘以权重，
+ * 例如在一列按钮中按左右键不会跳到另一列中较远的按钮。
+ * @param from 当前获得焦点的元素
+ * @param candidates 可获得焦点的元素
+ * @param direction 单位方向
+ * @return 最近的元素，该方向上没有元素时返回nil
+ */
+func findFocusNeighbor(from state.IUIInteractive, candidates []state.IUIInteractive, direction mgl32.Vec2) state.IUIInteractive {
+	origin := elementCenter(from)
+	var best state.IUIInteractive
+	bestScore := float32(math.MaxFloat32)
+	for _, candidate := range candidates {
+		if candidate == from {
+			continue
+		}
+		offset := elementCenter(candidate).Sub(origin)
+		along := offset.Dot(direction)
+		if along <= 0.0 {
+			continue
+		}
+		across := float32(math.Abs(float64(offset.X()*direction.Y() - offset.Y()*direction.X())))
+		if score := along + across*focusCrossWeight; score < bestScore {
+			best, bestScore = candidate, score
+		}
+	}
+	return best
+}
+
+/**
+ * @brief 设置获得焦点的元素
+ * @param element 获得焦点的元素，nil表示清除焦点
+ */
+func (um *UIManager) SetFocus(element state.IUIInteractive) {
+	if um.focused == element {
+		return
+	}
+	if um.focused != nil {
+		um.focused.SetFocused(false)
+	}
+	um.focused = element
+	if element != nil {
+		element.SetFocused(true)
+	}
+}
+
+// 获取获得焦点的元素，没有时返回nil
+func (um *UIManager) GetFocus() state.IUIInteractive {
+	return um.focused
+}
+
+// 设置取消回调，按下ui_cancel时调用，例如关闭菜单
+func (um *UIManager) SetCancelCallback(callback func()) {
+	um.cancelCallback = callback
+}
+
+// 设置焦点指示框颜色
+func (um *UIManager) SetFocusIndicatorColor(color emath.FColor) {
+	um.focusIndicatorColor = color
+}
+
 // 更新
 func (um *UIManager) Update(dt float64, context *econtext.Context) {
 	if !um.rootElement.IsVisible() {
 		return
 	}
+	um.elapsed += dt
 	// 从根元素开始向下更新
 	um.rootElement.Update(dt, context)
 }
@@ -72,6 +268,37 @@ func (um *UIManager) Render(context *econtext.Context) {
 	if !um.rootElement.IsVisible() {
 		return
 	}
+	// 渲染前重新布局，子元素的大小(例如标签文字)或逻辑分辨率变化后位置随之更新
+	um.Layout(context.GetGameState().GetLogicalSize())
 	// 从根元素开始向下渲染
 	um.rootElement.Render(context)
+	um.renderFocusIndicator(context)
+}
+
+// 在获得焦点的元素周围绘制闪烁的焦点指示框
+func (um *UIManager) renderFocusIndicator(context *econtext.Context) {
+	if um.focused == nil || !um.showFocusIndicator {
+		return
+	}
+	color := um.focusIndicatorColor
+	pulse := float32(math.Sin(um.elapsed*focusIndicatorPulseSpeed*2.0*math.Pi))*0.5 + 0.5
+	color.A *= 0.6 + 0.4*pulse
+	padding := mgl32.Vec2{focusIndicatorPadding, focusIndicatorPadding}
+	rect := emath.Rect{
+		Position: um.focused.GetScreenPosition().Sub(padding),
+		Size:     um.focused.GetSize().Add(padding.Mul(2.0)),
+	}
+	context.GetRenderer().DrawUIRect(rect, focusIndicatorThickness, color)
+}
+
+/**
+ * @brief 布局所有UI元素，根元素大小跟随逻辑分辨率
+ * @param logicalSize 逻辑分辨率，为0时保持根元素原有大小
+ */
+func (um *UIManager) Layout(logicalSize mgl32.Vec2) {
+	if logicalSize.X() > 0.0 && logicalSize.Y() > 0.0 && logicalSize != um.rootElement.GetSize() {
+		slog.Debug("ui root resized, re-layout", slog.Any("from", um.rootElement.GetSize()), slog.Any("to", logicalSize))
+		um.rootElement.SetSize(logicalSize)
+	}
+	um.rootElement.Layout()
 }
@@ -0,0 +1,137 @@
+package ui
+
+import (
+	econtext "sunny_land/src/engine/context"
+	"sunny_land/src/engine/render"
+	"sunny_land/src/engine/ui/state"
+	emath "sunny_land/src/engine/utils/math"
+
+	"github.com/SunshineZzzz/purego-sdl3/sdl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+/**
+ * @brief 使用九宫格绘制边框纹理的面板
+ *
+ * 与UIPanel一样用于分组其他UI元素，背景为像素风格的边框纹理，
+ * 四角保持原始尺寸、四边与中心拉伸，适用于对话框、菜单等任意尺寸的面板。
+ */
+type UINineSlicePanel struct {
+	// 继承UI元素基础实现
+	UIElement
+	// 边框精灵图
+	sprite *render.Sprite
+	// 源图中的边框宽度(像素)
+	insets emath.Insets
+	// 边框的绘制缩放
+	borderScale float32
+	// 是否根据子元素自动调整大小，大小为最大的子元素加上边框与内边距
+	fitContent bool
+	// 自动调整大小时边框内侧与子元素之间的内边距
+	padding emath.Insets
+}
+
+// 确保UINineSlicePanel实现IUIElement接口
+var _ state.IUIElement = (*UINineSlicePanel)(nil)
+
+/**
+ * @brief 构造一个UINineSlicePanel对象。
+ *
+ * @param textureId 边框纹理ID。
+ * @param position 面板的局部位置。
+ * @param size 面板的大小。
+ * @param insets 源图中的边框宽度(像素)。
+ * @param sourceRect 可选：要使用的纹理部分。（如果为空，则使用纹理的整个区域）
+ */
+func NewUINineSlicePanel(textureId string, position mgl32.Vec2, size mgl32.Vec2, insets emath.Insets, sourceRect *sdl.FRect) *UINineSlicePanel {
+	np := &UINineSlicePanel{
+		sprite:      render.NewSprite(textureId, sourceRect, false),
+		insets:      insets,
+		borderScale: 1.0,
+	}
+	BuildUIElement(&np.UIElement, position, size)
+	return np
+}
+
+// 渲染
+func (np *UINineSlicePanel) Render(ctx *econtext.Context) {
+	if !np.visible {
+		return
+	}
+
+	ctx.GetRenderer().DrawUINineSlice(np.sprite, np.GetBounds(), np.insets, np.borderScale)
+
+	// 调用基类渲染方法(绘制子节点)
+	np.UIElement.Render(ctx)
+}
+
+// 布局，自动调整大小时根据最大的子元素(含外边距)计算自身大小，子元素通常以锚点居中放置
+func (np *UINineSlicePanel) Layout() {
+	np.UIElement.Layout()
+	if !np.fitContent {
+		return
+	}
+	content := mgl32.Vec2{}
+	for child := np.children.Front(); child != nil; child = child.Next() {
+		element := child.Value.(state.IUIElement)
+		if !element.IsVisible() {
+			continue
+		}
+		margin := element.GetMargin()
+		content = mgl32.Vec2{
+			max(content.X(), element.GetSize().X()+margin.Horizontal()),
+			max(content.Y(), element.GetSize().Y()+margin.Vertical()),
+		}
+	}
+	np.size = content.Add(mgl32.Vec2{
+		np.insets.Horizontal()*np.borderScale + np.padding.Horizontal(),
+		np.insets.Vertical()*np.borderScale + np.padding.Vertical(),
+	})
+}
+
+/**
+ * @brief 设置根据子元素自动调整大小
+ * @param fitContent 是否自动调整大小
+ * @param padding 边框内侧与子元素之间的内边距
+ */
+func (np *UINineSlicePanel) SetFitContent(fitContent bool, padding emath.Insets) {
+	np.fitContent = fitContent
+	np.padding = padding
+}
+
+// 获取边框宽度
+func (np *UINineSlicePanel) GetInsets() emath.Insets {
+	return np.insets
+}
+
+// 设置边框宽度
+func (np *UINineSlicePanel) SetInsets(insets emath.Insets) {
+	np.insets = insets
+}
+
+// 获取边框的绘制缩放
+func (np *UINineSlicePanel) GetBorderScale() float32 {
+	return np.borderScale
+}
+
+// 设置边框的绘制缩放
+func (np *UINineSlicePanel) SetBorderScale(scale float32) {
+	np.borderScale = scale
+}
+
+// 获取内容区域(去掉边框后)相对于面板的矩形，用于摆放子元素
+func (np *UINineSlicePanel) GetContentRect() emath.Rect {
+	border := mgl32.Vec2{np.insets.Left * np.borderScale, np.insets.Top * np.borderScale}
+	return emath.Rect{
+		Position: border,
+		Size: mgl32.Vec2{
+			max(np.size.X()-np.insets.Horizontal()*np.borderScale, 0.0),
+			max(np.size.Y()-np.insets.Vertical()*np.borderScale, 0.0),
+		},
+	}
+}
+
+// 获取边框精灵图，例如用于设置颜色调制
+func (np *UINineSlicePanel) GetSprite() *render.Sprite {
+	return np.sprite
+}
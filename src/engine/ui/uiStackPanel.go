@@ -0,0 +1,166 @@
+package ui
+
+import (
+	"sunny_land/src/engine/ui/state"
+	emath "sunny_land/src/engine/utils/math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// 堆叠方向
+type StackDirection int
+
+const (
+	// 从上到下
+	StackVertical StackDirection = iota
+	// 从左到右
+	StackHorizontal
+)
+
+/**
+ * @brief 堆叠容器，布局时将可见的子元素沿一个方向依次排列
+ *
+ * 子元素之间相隔spacing，容器四周留出padding，子元素的外边距额外占用空间。
+ * 垂直于排列方向上按对齐比例对齐(0为左/上，0.5为居中，1为右/下)。
+ * 默认根据内容自动调整自身大小，可以配合锚点居中放置。
+ * 子元素的位置由容器设置，子元素应保持默认的锚点与轴心。
+ */
+type UIStackPanel struct {
+	// 继承UI元素基础实现
+	UIElement
+	// 堆叠方向
+	direction StackDirection
+	// 子元素之间的间距
+	spacing float32
+	// 容器四周的内边距
+	padding emath.Insets
+	// 垂直于排列方向的对齐比例
+	align float32
+	// 是否根据内容自动调整大小
+	fitContent bool
+}
+
+// 确保UIStackPanel实现IUIElement接口
+var _ state.IUIElement = (*UIStackPanel)(nil)
+
+/**
+ * @brief 构造一个UIStackPanel对象。
+ *
+ * @param direction 堆叠方向。
+ * @param spacing 子元素之间的间距。
+ * @param padding 容器四周的内边距。
+ */
+func NewUIStackPanel(direction StackDirection, spacing float32, padding emath.Insets) *UIStackPanel {
+	sp := &UIStackPanel{
+		direction:  direction,
+		spacing:    spacing,
+		padding:    padding,
+		align:      0.5,
+		fitContent: true,
+	}
+	BuildUIElement(&sp.UIElement, mgl32.Vec2{}, mgl32.Vec2{})
+	return sp
+}
+
+// 布局：先布局子元素(嵌套容器确定大小)，再排列子元素
+func (sp *UIStackPanel) Layout() {
+	sp.UIElement.Layout()
+
+	// 主轴为排列方向，交叉轴为垂直于排列方向
+	axis, cross := 1, 0
+	leading, trailing := sp.padding.Top, sp.padding.Bottom
+	crossLeading, crossTrailing := sp.padding.Left, sp.padding.Right
+	if sp.direction == StackHorizontal {
+		axis, cross = 0, 1
+		leading, trailing = sp.padding.Left, sp.padding.Right
+		crossLeading, crossTrailing = sp.padding.Top, sp.padding.Bottom
+	}
+
+	// 计算内容大小
+	mainLength, crossLength := float32(0.0), float32(0.0)
+	count := 0
+	for child := sp.children.Front(); child != nil; child = child.Next() {
+		element := child.Value.(state.IUIElement)
+		if !element.IsVisible() {
+			continue
+		}
+		before, after, crossBefore, crossAfter := sp.childMargins(element.GetMargin())
+		mainLength += element.GetSize()[axis] + before + after
+		crossLength = max(crossLength, element.GetSize()[cross]+crossBefore+crossAfter)
+		count++
+	}
+	if count > 1 {
+		mainLength += sp.spacing * float32(count-1)
+	}
+	if sp.fitContent {
+		size := mgl32.Vec2{}
+		size[axis] = leading + mainLength + trailing
+		size[cross] = crossLeading + crossLength + crossTrailing
+		sp.size = size
+	}
+	available := sp.size[cross] - crossLeading - crossTrailing
+
+	// 排列子元素
+	offset := leading
+	for child := sp.children.Front(); child != nil; child = child.Next() {
+		element := child.Value.(state.IUIElement)
+		if !element.IsVisible() {
+			continue
+		}
+		before, after, crossBefore, crossAfter := sp.childMargins(element.GetMargin())
+		childSize := element.GetSize()
+		position := mgl32.Vec2{}
+		position[axis] = offset + before
+		position[cross] = crossLeading + crossBefore + (available-childSize[cross]-crossBefore-crossAfter)*sp.align
+		element.SetPosition(position)
+		offset += before + childSize[axis] + after + sp.spacing
+	}
+}
+
+// 将子元素的外边距转换为主轴前后、交叉轴前后
+func (sp *UIStackPanel) childMargins(margin emath.Insets) (float32, float32, float32, float32) {
+	if sp.direction == StackHorizontal {
+		return margin.Left, margin.Right, margin.Top, margin.Bottom
+	}
+	return margin.Top, margin.Bottom, margin.Left, margin.Right
+}
+
+// 获取堆叠方向
+func (sp *UIStackPanel) GetDirection() StackDirection {
+	return sp.direction
+}
+
+// 设置堆叠方向
+func (sp *UIStackPanel) SetDirection(direction StackDirection) {
+	sp.direction = direction
+}
+
+// 获取子元素之间的间距
+func (sp *UIStackPanel) GetSpacing() float32 {
+	return sp.spacing
+}
+
+// 设置子元素之间的间距
+func (sp *UIStackPanel) SetSpacing(spacing float32) {
+	sp.spacing = spacing
+}
+
+// 获取内边距
+func (sp *UIStackPanel) GetPadding() emath.Insets {
+	return sp.padding
+}
+
+// 设置内边距
+func (sp *UIStackPanel) SetPadding(padding emath.Insets) {
+	sp.padding = padding
+}
+
+// 设置垂直于排列方向的对齐比例，0为左/上，0.5为居中，1为右/下
+func (sp *UIStackPanel) SetAlign(align float32) {
+	sp.align = emath.Clamp(align, 0.0, 1.0)
+}
+
+// 设置是否根据内容自动调整大小，关闭时使用SetSize设置的大小
+func (sp *UIStackPanel) SetFitContent(fitContent bool) {
+	sp.fitContent = fitContent
+}
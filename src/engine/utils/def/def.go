@@ -25,6 +25,10 @@ const (
 	ComponentTypeAI
 	// 音频组件
 	ComponentTypeAudio
+	// 粒子发射器组件
+	ComponentTypeParticleEmitter
+	// 光源组件
+	ComponentTypeLight
 
 	// 玩家组件
 	ComponentTypePlayer
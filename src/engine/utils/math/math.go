@@ -39,6 +39,26 @@ type Rect struct {
 	Size mgl32.Vec2
 }
 
+// 四边的内边距，例如九宫格的边框宽度
+type Insets struct {
+	Left, Top, Right, Bottom float32
+}
+
+// 创建四边相同的内边距
+func UniformInsets(value float32) Insets {
+	return Insets{Left: value, Top: value, Right: value, Bottom: value}
+}
+
+// 水平方向的内边距之和
+func (i Insets) Horizontal() float32 {
+	return i.Left + i.Right
+}
+
+// 垂直方向的内边距之和
+func (i Insets) Vertical() float32 {
+	return i.Top + i.Bottom
+}
+
 // 限制向量在min向量和max向量之间
 func Mgl32Vec2Clamp(vec, min, max mgl32.Vec2) mgl32.Vec2 {
 	return mgl32.Vec2{
@@ -94,3 +114,30 @@ func Clamp[T Number](value, min, max T) T {
 	}
 	return value
 }
+
+// 缓动函数，输入与输出均为0.0~1.0的进度
+type EaseFunc func(t float32) float32
+
+// 线性
+func EaseLinear(t float32) float32 {
+	return t
+}
+
+// 先加速后减速(二次)
+func EaseInOutQuad(t float32) float32 {
+	if t < 0.5 {
+		return 2.0 * t * t
+	}
+	return 1.0 - (-2.0*t+2.0)*(-2.0*t+2.0)/2.0
+}
+
+// 减速(三次)
+func EaseOutCubic(t float32) float32 {
+	inv := 1.0 - t
+	return 1.0 - inv*inv*inv
+}
+
+// 加速(三次)
+func EaseInCubic(t float32) float32 {
+	return t * t * t
+}
@@ -0,0 +1,325 @@
+package vfs
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// 默认的资源包与模组目录
+const (
+	// 打包的资源文件，zip格式，内容为assets目录下的文件，挂载到"assets"
+	AssetsArchive = "assets.pak"
+	// 模组目录，其中每个子目录或zip/pak文件按assets目录结构覆盖游戏资源
+	ModsDir = "mods"
+)
+
+// 挂载优先级，数值越大越优先，相同优先级后挂载的优先
+const (
+	// 打包的资源文件
+	VFSPriorityArchive = 0
+	// 散落的资源目录(开发时使用)
+	VFSPriorityDirectory = 10
+	// 模组目录，覆盖游戏自带的资源
+	VFSPriorityMod = 100
+)
+
+// 挂载点
+type vfsMount struct {
+	// 挂载名称，用于卸载，目录或归档文件路径
+	name string
+	// 挂载位置，例如"assets"，空表示根
+	mountPoint string
+	// 文件系统
+	fsys fs.FS
+	// 优先级
+	priority int
+	// 挂载顺序，相同优先级时后挂载的优先
+	order int
+	// 关闭函数，归档文件需要关闭
+	closer io.Closer
+}
+
+/**
+ * @brief 虚拟文件系统，将目录、zip/pak归档或embed.FS挂载到统一的路径空间。
+ *
+ * 游戏中使用的路径(例如"assets/textures/a.png")按优先级从高到低依次在各挂载点中查找，
+ * 找到即返回，因此高优先级的挂载(例如模组目录)可以覆盖低优先级挂载中的同名文件。
+ */
+type VFS struct {
+	// 读写锁，预加载等后台协程可能同时读取
+	sync.RWMutex
+	// 挂载点，按优先级从高到低排序
+	mounts []*vfsMount
+	// 下一个挂载顺序
+	nextOrder int
+}
+
+// 默认虚拟文件系统，挂载了当前工作目录，资源加载器统一通过它读取文件
+var defaultVFS = newDefaultVFS()
+
+// 创建默认虚拟文件系统
+func newDefaultVFS() *VFS {
+	v := NewVFS()
+	v.MountDir(".", "", VFSPriorityDirectory)
+	return v
+}
+
+// 获取默认虚拟文件系统
+func GetVFS() *VFS {
+	return defaultVFS
+}
+
+/**
+ * @brief 挂载游戏资源包与模组，优先级：模组 > 散落的资源目录 > 资源包
+ *
+ * 游戏与关卡校验命令使用同样的挂载，校验的就是游戏实际读取的文件。
+ * @param archivePath 资源包路径，zip格式，内容为assets目录下的文件，不存在时跳过
+ * @param modsDir 模组目录，其中每个子目录或zip/pak文件按assets目录结构覆盖游戏资源，不存在时跳过
+ */
+func (v *VFS) MountAssets(archivePath, modsDir string) {
+	if _, err := os.Stat(archivePath); err == nil {
+		v.MountArchive(archivePath, "assets", VFSPriorityArchive)
+	}
+
+	entries, err := os.ReadDir(modsDir)
+	if err != nil {
+		return
+	}
+	// 按名称顺序挂载，名称靠后的模组优先
+	for _, entry := range entries {
+		modPath := filepath.Join(modsDir, entry.Name())
+		if entry.IsDir() {
+			v.MountDir(modPath, "assets", VFSPriorityMod)
+			continue
+		}
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".zip", ".pak":
+			v.MountArchive(modPath, "assets", VFSPriorityMod)
+		}
+	}
+}
+
+// 创建虚拟文件系统
+func NewVFS() *VFS {
+	return &VFS{
+		mounts: make([]*vfsMount, 0),
+	}
+}
+
+/**
+ * @brief 挂载目录
+ * @param dir 磁盘目录
+ * @param mountPoint 挂载位置，例如"assets"，空表示根
+ * @param priority 优先级
+ * @return bool 是否成功
+ */
+func (v *VFS) MountDir(dir, mountPoint string, priority int) bool {
+	info, err := os.Stat(dir)
+	if err != nil || !info.IsDir() {
+		slog.Error("vfs mount dir failed, not a directory", slog.String("dir", dir))
+		return false
+	}
+	v.mount(&vfsMount{name: dir, mountPoint: mountPoint, fsys: os.DirFS(dir), priority: priority})
+	return true
+}
+
+/**
+ * @brief 挂载zip/pak归档文件，pak即改了扩展名的zip文件
+ * @param archivePath 归档文件路径
+ * @param mountPoint 挂载位置，例如"assets"，空表示根
+ * @param priority 优先级
+ * @return bool 是否成功
+ */
+func (v *VFS) MountArchive(archivePath, mountPoint string, priority int) bool {
+	reader, err := zip.OpenReader(archivePath)
+	if err != nil {
+		slog.Error("vfs mount archive failed", slog.String("archivePath", archivePath), slog.String("error", err.Error()))
+		return false
+	}
+	v.mount(&vfsMount{name: archivePath, mountPoint: mountPoint, fsys: reader, priority: priority, closer: reader})
+	return true
+}
+
+/**
+ * @brief 挂载任意fs.FS，例如embed.FS
+ * @param name 挂载名称，用于卸载
+ * @param fsys 文件系统
+ * @param mountPoint 挂载位置，例如"assets"，空表示根
+ * @param priority 优先级
+ */
+func (v *VFS) MountFS(name string, fsys fs.FS, mountPoint string, priority int) {
+	v.mount(&vfsMount{name: name, mountPoint: mountPoint, fsys: fsys, priority: priority})
+}
+
+// 添加挂载点并按优先级排序
+func (v *VFS) mount(m *vfsMount) {
+	v.Lock()
+	defer v.Unlock()
+
+	m.mountPoint = cleanVFSPath(m.mountPoint)
+	m.order = v.nextOrder
+	v.nextOrder++
+	v.mounts = append(v.mounts, m)
+	sort.SliceStable(v.mounts, func(i, j int) bool {
+		if v.mounts[i].priority != v.mounts[j].priority {
+			return v.mounts[i].priority > v.mounts[j].priority
+		}
+		return v.mounts[i].order > v.mounts[j].order
+	})
+	slog.Info("vfs mounted", slog.String("name", m.name), slog.String("mountPoint", m.mountPoint), slog.Int("priority", m.priority))
+}
+
+// 卸载指定名称的挂载点
+func (v *VFS) Unmount(name string) bool {
+	v.Lock()
+	defer v.Unlock()
+
+	for i, m := range v.mounts {
+		if m.name != name {
+			continue
+		}
+		if m.closer != nil {
+			m.closer.Close()
+		}
+		v.mounts = append(v.mounts[:i], v.mounts[i+1:]...)
+		slog.Info("vfs unmounted", slog.String("name", name))
+		return true
+	}
+	slog.Warn("vfs mount not found, can not unmount", slog.String("name", name))
+	return false
+}
+
+// 卸载所有挂载点
+func (v *VFS) Close() {
+	v.Lock()
+	defer v.Unlock()
+
+	for _, m := range v.mounts {
+		if m.closer != nil {
+			m.closer.Close()
+		}
+	}
+	v.mounts = make([]*vfsMount, 0)
+}
+
+// 打开文件，按优先级查找
+func (v *VFS) Open(filePath string) (fs.File, error) {
+	v.RLock()
+	defer v.RUnlock()
+
+	name := cleanVFSPath(filePath)
+	for _, m := range v.mounts {
+		relPath, ok := m.relPath(name)
+		if !ok {
+			continue
+		}
+		file, err := m.fsys.Open(relPath)
+		if err == nil {
+			return file, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("vfs open %s in %s: %w", filePath, m.name, err)
+		}
+	}
+	return nil, &fs.PathError{Op: "open", Path: filePath, Err: fs.ErrNotExist}
+}
+
+// 读取整个文件
+func (v *VFS) ReadFile(filePath string) ([]byte, error) {
+	file, err := v.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return io.ReadAll(file)
+}
+
+// 文件是否存在
+func (v *VFS) Exists(filePath string) bool {
+	file, err := v.Open(filePath)
+	if err != nil {
+		return false
+	}
+	file.Close()
+	return true
+}
+
+// 获取文件所在挂载点的名称，用于调试模组覆盖，文件不存在返回空字符串
+func (v *VFS) Which(filePath string) string {
+	v.RLock()
+	defer v.RUnlock()
+
+	name := cleanVFSPath(filePath)
+	for _, m := range v.mounts {
+		if relPath, ok := m.relPath(name); ok {
+			if _, err := fs.Stat(m.fsys, relPath); err == nil {
+				return m.name
+			}
+		}
+	}
+	return ""
+}
+
+/**
+ * @brief 在所有挂载点中查找匹配的文件，结果去重并排序
+ * @param pattern 匹配模式，语法与path.Match相同，例如"assets/maps/*.tmj"
+ * @return []string 匹配的文件路径
+ */
+func (v *VFS) Glob(pattern string) ([]string, error) {
+	v.RLock()
+	defer v.RUnlock()
+
+	pattern = cleanVFSPath(pattern)
+	found := make(map[string]bool)
+	for _, m := range v.mounts {
+		relPattern, ok := m.relPath(pattern)
+		if !ok {
+			continue
+		}
+		matches, err := fs.Glob(m.fsys, relPattern)
+		if err != nil {
+			return nil, fmt.Errorf("vfs glob %s in %s: %w", pattern, m.name, err)
+		}
+		for _, match := range matches {
+			if m.mountPoint != "." {
+				match = path.Join(m.mountPoint, match)
+			}
+			found[match] = true
+		}
+	}
+	result := make([]string, 0, len(found))
+	for match := range found {
+		result = append(result, match)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// 获取路径相对于挂载位置的路径，不在挂载位置下返回false
+func (m *vfsMount) relPath(name string) (string, bool) {
+	if m.mountPoint == "." {
+		return name, true
+	}
+	if name == m.mountPoint {
+		return ".", true
+	}
+	if strings.HasPrefix(name, m.mountPoint+"/") {
+		return name[len(m.mountPoint)+1:], true
+	}
+	return "", false
+}
+
+// 规范化路径，统一使用'/'分隔，去掉"./"前缀，空路径为"."
+func cleanVFSPath(filePath string) string {
+	return path.Clean(filepath.ToSlash(filePath))
+}
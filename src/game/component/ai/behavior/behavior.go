@@ -0,0 +1,21 @@
+package behavior
+
+/**
+ * @brief 游戏内置AI行为的名称，即地图"ai"属性中的"behavior"字段。
+ *
+ * 单独成包、不依赖SDL，关卡校验命令通过Names校验地图中引用的行为，
+ * ai.RegisterBehaviors按这些名称注册，新增行为时需要同时加入Names。
+ */
+const (
+	// 左右巡逻
+	Patrol = "patrol"
+	// 上下移动
+	UpDown = "updown"
+	// 左右跳跃
+	Jump = "jump"
+)
+
+// 获取所有内置AI行为的名称
+func Names() []string {
+	return []string{Patrol, UpDown, Jump}
+}
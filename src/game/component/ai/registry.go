@@ -0,0 +1,57 @@
+package ai
+
+import (
+	"sunny_land/src/engine/component"
+	"sunny_land/src/game/component/ai/behavior"
+
+	"github.com/bitly/go-simplejson"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+/**
+ * @brief 注册游戏内置的AI行为，地图中通过"ai"属性按名称引用。
+ *
+ * 通用参数：
+ * "range"  行为范围(像素)，以初始位置为右(下)边界向左(上)延伸
+ * "offset" 边界相对初始位置的偏移量(像素)，默认0
+ *
+ * patrol: {"behavior":"patrol","range":200,"speed":50}
+ * updown: {"behavior":"updown","range":80,"speed":50}
+ * jump:   {"behavior":"jump","range":90,"offset":-10,"velocity":[100,-300],"interval":2.0}
+ */
+func RegisterBehaviors() {
+	component.RegisterAIBehavior(behavior.Patrol, newPatrolBehaviorFromJson)
+	component.RegisterAIBehavior(behavior.UpDown, newUpDownBehaviorFromJson)
+	component.RegisterAIBehavior(behavior.Jump, newJumpBehaviorFromJson)
+}
+
+// 从json参数创建巡逻行为
+func newPatrolBehaviorFromJson(params *simplejson.Json, position mgl32.Vec2) component.IAIBehavior {
+	maxX := position.X() + float32(params.Get("offset").MustFloat64(0.0))
+	minX := maxX - float32(params.Get("range").MustFloat64(200.0))
+	speed := float32(params.Get("speed").MustFloat64(50.0))
+	return NewPatrolBehavior(minX, maxX, speed)
+}
+
+// 从json参数创建上下移动行为
+func newUpDownBehaviorFromJson(params *simplejson.Json, position mgl32.Vec2) component.IAIBehavior {
+	maxY := position.Y() + float32(params.Get("offset").MustFloat64(0.0))
+	minY := maxY - float32(params.Get("range").MustFloat64(80.0))
+	speed := float32(params.Get("speed").MustFloat64(50.0))
+	return NewUpDownBehavior(minY, maxY, speed)
+}
+
+// 从json参数创建跳跃行为
+func newJumpBehaviorFromJson(params *simplejson.Json, position mgl32.Vec2) component.IAIBehavior {
+	maxX := position.X() + float32(params.Get("offset").MustFloat64(0.0))
+	minX := maxX - float32(params.Get("range").MustFloat64(90.0))
+	velocity := mgl32.Vec2{100.0, -300.0}
+	if vel, ok := params.CheckGet("velocity"); ok && len(vel.MustArray()) == 2 {
+		velocity = mgl32.Vec2{
+			float32(vel.GetIndex(0).MustFloat64(100.0)),
+			float32(vel.GetIndex(1).MustFloat64(-300.0)),
+		}
+	}
+	interval := params.Get("interval").MustFloat64(2.0)
+	return NewJumpBehavior(minX, maxX, velocity, interval)
+}
@@ -48,6 +48,8 @@ type PlayerComponent struct {
 	coyoteTimeTimer float64
 	// 无敌闪烁间隔时间，单位：秒
 	flashInterval float64
+	// 无敌闪烁时半透明阶段的不透明度
+	flashAlpha float32
 	// 无敌闪烁计时器
 	flashTimer float64
 }
@@ -72,6 +74,7 @@ func NewPlayerComponent() *PlayerComponent {
 		stunnedDuration: 0.4,
 		coyoteTime:      0.1,
 		flashInterval:   0.1,
+		flashAlpha:      0.3,
 	}
 }
 
@@ -177,17 +180,17 @@ func (p *PlayerComponent) Update(dt float64, ctx physics.IContext) {
 			p.flashTimer -= 2.0 * p.flashInterval
 		}
 
-		// 一半时间可见，一半时间不可见
+		// 一半时间半透明，一半时间不透明
 		if p.flashTimer < p.flashInterval {
-			// 前0.1秒内不可见
-			p.spriteCom.SetHidden(true)
+			// 前0.1秒内半透明
+			p.spriteCom.SetAlpha(p.flashAlpha)
 		} else {
-			// 后0.1秒内可见
-			p.spriteCom.SetHidden(false)
+			// 后0.1秒内不透明
+			p.spriteCom.SetAlpha(1.0)
 		}
 	} else {
-		// 不是无敌状态，确保精灵图可见
-		p.spriteCom.SetHidden(false)
+		// 不是无敌状态，确保精灵图不透明
+		p.spriteCom.SetAlpha(1.0)
 	}
 
 	nextState := p.currentState.Update(dt, ctx)
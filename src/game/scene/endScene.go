@@ -74,6 +74,11 @@ func (es *EndScene) createUI() {
 		messageColor = emath.FColor{R: 1.0, G: 0.0, B: 0.0, A: 1.0}
 	}
 
+	// 主标签与得分标签从上到下排列，水平居中，垂直位置在30%处
+	labels := ui.NewUIStackPanel(ui.StackVertical, 10.0, emath.Insets{})
+	labels.SetAnchor(mgl32.Vec2{0.5, 0.3}, ui.AnchorTop, mgl32.Vec2{0.0, 0.0})
+	es.UIManager.AddElement(labels)
+
 	mainLabel := ui.NewUILabel(es.GetContext().GetTextRenderer(),
 		main_message,
 		"assets/fonts/VonwaonBitmap-16px.ttf",
@@ -82,11 +87,9 @@ func (es *EndScene) createUI() {
 		mgl32.Vec2{0.0, 0.0},
 	)
 
-	// 标签居中
-	label_size := mainLabel.GetSize()
-	main_label_pos := mgl32.Vec2{(window_size.X() - label_size.X()) / 2.0, window_size.Y() * 0.3}
-	mainLabel.SetPosition(main_label_pos)
-	es.UIManager.AddElement(mainLabel)
+	// 与得分标签之间额外间隔10像素
+	mainLabel.SetMargin(emath.Insets{Bottom: 10.0})
+	labels.AddChild(mainLabel)
 
 	// 得分标签
 	currentScore := es.sessionData.GetCurrentScore()
@@ -103,10 +106,7 @@ func (es *EndScene) createUI() {
 		scoreColor,
 		mgl32.Vec2{0.0, 0.0},
 	)
-	scoreLabelSize := score_label.GetSize()
-	// x方向居中，y方向在主标签下方20像素
-	score_label.SetPosition(mgl32.Vec2{(window_size.X() - scoreLabelSize.X()) / 2.0, main_label_pos.Y() + label_size.Y() + 20.0})
-	es.UIManager.AddElement(score_label)
+	labels.AddChild(score_label)
 
 	// 最高分
 	highScoreText := "High Score: " + strconv.Itoa(highScore)
@@ -117,52 +117,52 @@ func (es *EndScene) createUI() {
 		scoreColor,
 		mgl32.Vec2{0.0, 0.0},
 	)
-	highScoreLabelSize := high_score_label.GetSize()
-	// x方向居中，y方向在当前得分下方10像素
-	high_score_label.SetPosition(mgl32.Vec2{(window_size.X() - highScoreLabelSize.X()) / 2.0, score_label.GetPosition().Y() + scoreLabelSize.Y() + 10.0})
-	es.UIManager.AddElement(high_score_label)
+	labels.AddChild(high_score_label)
 
 	// UI按钮
 	// 让按钮更大一点
 	buttonSize := mgl32.Vec2{120.0, 40.0}
 	buttonSpacing := float32(20.0)
-	totalButtonWidth := buttonSize.X()*2.0 + buttonSpacing
 
-	// 按钮放在右下角，与边缘间隔30像素
-	buttonsX := window_size.X() - totalButtonWidth - 30.0
-	buttonsY := window_size.Y() - buttonSize.Y() - 30.0
+	// 按钮水平排列，放在右下角，与边缘间隔30像素
+	buttons := ui.NewUIStackPanel(ui.StackHorizontal, buttonSpacing, emath.Insets{})
+	buttons.SetAnchor(ui.AnchorBottomRight, ui.AnchorBottomRight, mgl32.Vec2{-30.0, -30.0})
+	es.UIManager.AddElement(buttons)
 	// Back Button
 	backButton := ui.NewUIButton(es.GetContext(),
 		"assets/textures/UI/buttons/Back1.png",
 		"assets/textures/UI/buttons/Back2.png",
 		"assets/textures/UI/buttons/Back3.png",
-		mgl32.Vec2{buttonsX, buttonsY},
+		mgl32.Vec2{0.0, 0.0},
 		buttonSize,
 		func() { es.onBackClick() },
 	)
-	es.UIManager.AddElement(backButton)
+	buttons.AddChild(backButton)
 
 	// Restart Button
 	restartButton := ui.NewUIButton(es.GetContext(),
 		"assets/textures/UI/buttons/Restart1.png",
 		"assets/textures/UI/buttons/Restart2.png",
 		"assets/textures/UI/buttons/Restart3.png",
-		mgl32.Vec2{buttonsX + buttonSize.X() + buttonSpacing, buttonsY},
+		mgl32.Vec2{0.0, 0.0},
 		buttonSize,
 		func() { es.onRestartClick() },
 	)
-	es.UIManager.AddElement(restartButton)
+	buttons.AddChild(restartButton)
+
+	// 取消键返回标题场景
+	es.UIManager.SetCancelCallback(func() { es.onBackClick() })
 }
 
 // 返回按钮点击事件
 func (es *EndScene) onBackClick() {
 	slog.Info("Back button clicked")
-	es.SceneManager.RequestReplaceScene(NewTitleScene(es.GetContext(), es.SceneManager, es.sessionData))
+	es.SceneManager.RequestReplaceSceneWithTransition(NewTitleScene(es.GetContext(), es.SceneManager, es.sessionData), newSceneFade())
 }
 
 // 重新开始按钮点击事件
 func (es *EndScene) onRestartClick() {
 	slog.Info("Restart button clicked")
 	es.sessionData.Reset()
-	es.SceneManager.RequestReplaceScene(NewGameScene(es.GetContext(), es.SceneManager, es.sessionData))
+	es.SceneManager.RequestReplaceSceneWithTransition(NewGameSceneWithLoading(es.GetContext(), es.SceneManager, es.sessionData), newSceneFade())
 }
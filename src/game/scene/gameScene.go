@@ -2,20 +2,23 @@ package scene
 
 import (
 	"log/slog"
+	"math"
+	"path/filepath"
 	"strconv"
+	"strings"
 
 	"sunny_land/src/engine/component"
 	econtext "sunny_land/src/engine/context"
 	"sunny_land/src/engine/object"
 	"sunny_land/src/engine/physics"
 	"sunny_land/src/engine/render"
+	"sunny_land/src/engine/resource"
 	escene "sunny_land/src/engine/scene"
 	"sunny_land/src/engine/ui"
 	"sunny_land/src/engine/utils"
 	"sunny_land/src/engine/utils/def"
 	emath "sunny_land/src/engine/utils/math"
 	gcomponent "sunny_land/src/game/component"
-	"sunny_land/src/game/component/ai"
 	"sunny_land/src/game/data"
 
 	"github.com/SunshineZzzz/purego-sdl3/sdl"
@@ -34,11 +37,76 @@ type GameScene struct {
 	scoreLabel *ui.UILabel
 	// 生命值面板
 	healthPanel *ui.UIPanel
+	// 关卡加载器，保留以便访问关卡及图层属性
+	levelLoader escene.ILevelLoader
+	// 剩余时间(秒)，关卡没有时间限制时不使用
+	timeRemaining float64
+	// 剩余时间标签，关卡没有时间限制时为nil
+	timeLabel *ui.UILabel
+	// 热重载时保留的玩家与相机位置，nil表示使用地图中的位置
+	reloadState *reloadState
+	// 是否已经请求热重载，避免同一轮多个文件变化重复重建场景
+	reloadRequested bool
+	// 上一帧玩家是否着地及下落速度，用于检测落地
+	wasGrounded   bool
+	lastFallSpeed float32
+	// 已加载的粒子发射器配置，路径 -> 配置
+	particleConfigs map[string]*component.ParticleEmitterConfig
+}
+
+// 热重载时需要保留的状态
+type reloadState struct {
+	// 玩家位置
+	playerPosition mgl32.Vec2
+	// 相机位置
+	cameraPosition mgl32.Vec2
+}
+
+const (
+	// 默认背景音乐
+	defaultLevelMusic = "assets/audio/hurry_up_and_run.ogg"
+	// 游戏场景的预加载清单
+	gamePreloadManifest = "assets/manifests/game.json"
+	// 前视距离与平滑速度
+	cameraLookAheadDistance = 48.0
+	cameraLookAheadSpeed    = 3.0
+	// 受伤与踩踏时相机增加的创伤值
+	cameraDamageTrauma = 0.5
+	cameraStompTrauma  = 0.15
+	// 落地扬尘与拾取宝石闪光的粒子发射器配置
+	dustParticles    = "assets/particles/dust.json"
+	sparkleParticles = "assets/particles/sparkle.json"
+	// 产生落地扬尘的最小下落速度(像素/秒)
+	dustMinFallSpeed = 150.0
+	// 玩家光源半径，只在有环境光的关卡中可见
+	playerLightRadius = 72.0
+	// 场景切换的淡入淡出时长与进入下一关卡的圆形转场时长(秒)
+	sceneFadeDuration = 0.6
+	levelIrisDuration = 1.2
+)
+
+var (
+	// 相机死区大小，玩家在其中移动时相机不动
+	cameraDeadzone = mgl32.Vec2{32.0, 96.0}
+	// 踩踏时相机的冲击位移
+	cameraStompKick = mgl32.Vec2{0.0, 4.0}
+	// 玩家光源颜色，暖色，A为强度
+	playerLightColor = emath.FColor{R: 1.0, G: 0.9, B: 0.75, A: 0.9}
+	// 转场颜色
+	transitionColor = emath.FColor{R: 0.0, G: 0.0, B: 0.0, A: 1.0}
+)
+
+// 创建场景切换使用的淡入淡出转场
+func newSceneFade() *escene.Transition {
+	return escene.NewFadeTransition(sceneFadeDuration, transitionColor)
 }
 
 // 确保GameScene实现IScene接口
 var _ escene.IScene = (*GameScene)(nil)
 
+// 确保GameScene实现IFileReloadable接口
+var _ escene.IFileReloadable = (*GameScene)(nil)
+
 // 创建游戏场景
 func NewGameScene(ctx *econtext.Context, sceneManager *escene.SceneManager, sd *data.SessionData) *GameScene {
 	gs := &GameScene{}
@@ -51,6 +119,19 @@ func NewGameScene(ctx *econtext.Context, sceneManager *escene.SceneManager, sd *
 	return gs
 }
 
+// 创建游戏场景，先进入加载场景预加载清单中的资源，清单读取失败时直接进入游戏场景
+func NewGameSceneWithLoading(ctx *econtext.Context, sceneManager *escene.SceneManager, sd *data.SessionData) escene.IScene {
+	gameScene := NewGameScene(ctx, sceneManager, sd)
+	manifest := resource.LoadManifest(gamePreloadManifest)
+	if manifest == nil {
+		return gameScene
+	}
+	loadingScene := escene.NewLoadingScene(ctx, sceneManager, manifest, gameScene)
+	loadingScene.SetFont("assets/fonts/VonwaonBitmap-16px.ttf", 16)
+	loadingScene.SetTransition(newSceneFade())
+	return loadingScene
+}
+
 // 初始化游戏场景
 func (gs *GameScene) Init() {
 	gs.Scene.Init()
@@ -93,8 +174,12 @@ func (gs *GameScene) Init() {
 	gs.GetContext().AudioPlayer.SetMusicVolume(0.2)
 	// 设置音效音量为50%
 	gs.GetContext().AudioPlayer.SetSoundVolume(0.5)
-	// 播放背景音乐
-	gs.GetContext().AudioPlayer.PlayMusic("assets/audio/hurry_up_and_run.ogg", true)
+	// 播放背景音乐，关卡没有指定时使用默认音乐
+	music := gs.levelLoader.GetLevelProperties().Music
+	if music == "" {
+		music = defaultLevelMusic
+	}
+	gs.GetContext().AudioPlayer.PlayMusic(music, true)
 
 	slog.Debug("GameScene initialized", slog.String("sceneName", gs.GetName()))
 }
@@ -103,10 +188,12 @@ func (gs *GameScene) Init() {
 func (gs *GameScene) InitLevel() bool {
 	// 加载关卡
 	levelPath := gs.sessionData.GetMapPath()
-	if !escene.NewLevelLoader().LoadLevel(levelPath, gs) {
-		slog.Error("level1.tmj load failed")
+	gs.levelLoader = escene.NewLevelLoaderByPath(levelPath)
+	if !gs.levelLoader.LoadLevel(levelPath, gs) {
+		slog.Error("level load failed", slog.String("levelPath", levelPath))
 		return false
 	}
+	levelProperties := gs.levelLoader.GetLevelProperties()
 
 	// 注册场景中的main层到物理引擎，main层会有物理属性
 	mainLayer := gs.FindGameObjectByName("main")
@@ -126,20 +213,47 @@ func (gs *GameScene) InitLevel() bool {
 
 	// 世界大小
 	worldSize := mainLayer.GetComponent(def.ComponentTypeTileLayer).(*component.TileLayerComponent).GetWorldSize()
-	// 设置相机限制范围
-	gs.GetContext().Camera.SetLimitBounds(&emath.Rect{Position: mgl32.Vec2{0.0, 0.0}, Size: worldSize})
+	// 设置相机限制范围，关卡没有指定时使用世界大小
+	cameraBounds := levelProperties.CameraBounds
+	if cameraBounds == nil {
+		cameraBounds = &emath.Rect{Position: mgl32.Vec2{0.0, 0.0}, Size: worldSize}
+	}
+	gs.GetContext().Camera.SetLimitBounds(cameraBounds)
 	// 开始时重置相机位置，以免切换场景时晃动
 	gs.GetContext().Camera.SetPosition(mgl32.Vec2{0.0, 0.0})
 
 	// 设置世界边界
 	gs.GetContext().PhysicsEngine.SetWorldBounds(&emath.Rect{Position: mgl32.Vec2{0.0, 0.0}, Size: worldSize})
 
+	// 设置重力，关卡没有指定时恢复默认值，避免沿用上一关卡的设置
+	if levelProperties.Gravity != nil {
+		gs.GetContext().PhysicsEngine.SetGravity(*levelProperties.Gravity)
+	} else {
+		gs.GetContext().PhysicsEngine.SetGravity(physics.DefaultGravity)
+	}
+
+	// 设置背景颜色
+	if levelProperties.BackgroundColor != nil {
+		gs.GetContext().Renderer.SetClearColor(*levelProperties.BackgroundColor)
+	}
+
+	// 设置环境光，洞穴或夜晚关卡启用光照
+	gs.SetAmbientLight(levelProperties.AmbientLight)
+
+	// 设置时间限制
+	gs.timeRemaining = levelProperties.TimeLimit
+
 	slog.Debug("GameScene level initialized", slog.String("sceneName", gs.GetName()))
 	return true
 }
 
 // 根据关卡名称获取对应的地图文件路径
+// 没有扩展名的名称视为Tiled地图，例如"level2"，LDtk关卡需要写明项目文件，例如"world.ldtk#Level_1"
 func (gs *GameScene) levelNameToPath(levelName string) string {
+	projectName, _, _ := strings.Cut(levelName, "#")
+	if filepath.Ext(projectName) != "" {
+		return "assets/maps/" + levelName
+	}
 	return "assets/maps/" + levelName + ".tmj"
 }
 
@@ -170,7 +284,35 @@ func (gs *GameScene) InitPlayer() bool {
 		slog.Error("player object transform component not found")
 		return false
 	}
-	gs.GetContext().Camera.SetTargetTC(transformComp)
+	camera := gs.GetContext().Camera
+	camera.ResetEffects()
+	camera.SetTargetTC(transformComp)
+	// 取景：水平死区与前视，垂直方向只在着地(或攀爬)时重新居中
+	physicsComp := gs.playerObject.GetComponent(def.ComponentTypePhysics).(*component.PhysicsComponent)
+	camera.SetDeadzone(cameraDeadzone)
+	camera.SetLookAhead(cameraLookAheadDistance, cameraLookAheadSpeed)
+	camera.SetVerticalMode(render.CameraVerticalOnLanding, func() bool {
+		return physicsComp.HasCollidedBelow() || physicsComp.HasCollidedLadder()
+	})
+	// 相机区域，根据玩家位置确定初始区域，不在区域内时使用关卡的限制范围
+	camera.SetZones(gs.levelLoader.GetLevelProperties().CameraZones, camera.GetLimitBounds())
+
+	// 玩家光源，位于碰撞盒中心
+	lightCom := component.NewLightComponent("", mgl32.Vec2{playerLightRadius * 2.0, playerLightRadius * 2.0}, playerLightColor)
+	if gs.playerObject.AddComponent(lightCom) == nil {
+		slog.Error("player light component init failed")
+		return false
+	}
+	if colliderComp, ok := gs.playerObject.GetComponent(def.ComponentTypeCollider).(*component.ColliderComponent); ok {
+		aabb := colliderComp.GetWorldAABB()
+		lightCom.SetOffset(aabb.Position.Add(aabb.Size.Mul(0.5)).Sub(transformComp.GetPosition()))
+	}
+
+	// 热重载重建的场景，玩家与相机回到重载前的位置
+	if gs.reloadState != nil {
+		transformComp.SetPosition(gs.reloadState.playerPosition)
+		camera.SetPosition(gs.reloadState.cameraPosition)
+	}
 
 	slog.Debug("player object transform component set to camera target")
 	return true
@@ -179,37 +321,23 @@ func (gs *GameScene) InitPlayer() bool {
 // 初始化敌人和道具
 func (gs *GameScene) InitEnemiesAndItem() bool {
 	success := true
+	// 敌人的AI行为由关卡加载器根据地图中的"ai"属性创建
 	for e := gs.GameObjects.Front(); e != nil; e = e.Next() {
 		gt := e.Value.(*object.GameObject)
-		switch gt.GetName() {
-		case "eagle":
-			aiCom := component.NewAIComponent()
-			if gt.AddComponent(aiCom) != nil {
-				yMax := gt.GetComponent(def.ComponentTypeTransform).(*component.TransformComponent).GetPosition().Y()
-				yMin := yMax - 80.0
-				aiCom.SetBehavior(ai.NewUpDownBehavior(yMin, yMax, 50.0))
-			}
-		case "frog":
-			aiCom := component.NewAIComponent()
-			if gt.AddComponent(aiCom) != nil {
-				xMax := gt.GetComponent(def.ComponentTypeTransform).(*component.TransformComponent).GetPosition().X() - 10.0
-				xMin := xMax - 90.0
-				aiCom.SetBehavior(ai.NewJumpBehavior(xMin, xMax, mgl32.Vec2{100.0, -300.0}, 2.0))
-			}
-		case "opossum":
-			aiCom := component.NewAIComponent()
-			if gt.AddComponent(aiCom) != nil {
-				xMax := gt.GetComponent(def.ComponentTypeTransform).(*component.TransformComponent).GetPosition().X()
-				xMin := xMax - 200.0
-				aiCom.SetBehavior(ai.NewPatrolBehavior(xMin, xMax, 50.0))
+		// 没有AI组件的敌人保持静止，被踩踏时通过生命组件受伤，见damageEnemy
+		if gt.GetTag() == "enemy" && !gt.HasComponent(def.ComponentTypeAI) {
+			slog.Warn("enemy object has no ai component, missing or invalid ai property", slog.String("name", gt.GetName()))
+			if !gt.HasComponent(def.ComponentTypeHealth) {
+				slog.Warn("enemy object has no health component either, it can not be defeated", slog.String("name", gt.GetName()))
 			}
 		}
 
 		if gt.GetTag() == "item" {
-			ac := gt.GetComponent(def.ComponentTypeAnimation).(*component.AnimationComponent)
-			if ac == nil {
-				slog.Error("item object animation component not found")
+			ac, ok := gt.GetComponent(def.ComponentTypeAnimation).(*component.AnimationComponent)
+			if !ok {
+				slog.Error("item object animation component not found", slog.String("name", gt.GetName()))
 				success = false
+				continue
 			}
 			ac.PlayAnimation("idle")
 		}
@@ -225,6 +353,9 @@ func (gs *GameScene) InitUI() bool {
 
 	gs.createScoreUI()
 	gs.createHealthPanel()
+	if gs.timeRemaining > 0.0 {
+		gs.createTimeUI()
+	}
 
 	return true
 }
@@ -237,6 +368,11 @@ func (gs *GameScene) Update(dt float64) {
 	// 处理瓦片触发事件
 	gs.handleTileTriggers()
 
+	// 关卡限时，时间耗尽则判断为失败
+	gs.updateTimeLimit(dt)
+	// 落地扬尘
+	gs.updateLandingDust()
+
 	// 玩家掉出地图下方则判断为失败
 	if gs.playerObject != nil {
 		pos := gs.playerObject.GetComponent(def.ComponentTypeTransform).(*component.TransformComponent).GetPosition()
@@ -264,9 +400,61 @@ func (gs *GameScene) HandleInput() {
 
 // 清理
 func (gs *GameScene) Clean() {
+	// 恢复默认背景颜色
+	gs.GetContext().Renderer.SetClearColor(emath.FColor{R: 0.0, G: 0.0, B: 0.0, A: 1.0})
 	gs.Scene.Clean()
 }
 
+/**
+ * @brief 文件发生变化，当前地图或图块集被修改时在玩家当前位置重建场景
+ * 纹理、字体、音频由资源管理器原地重新加载，这里不需要处理
+ * @param path 发生变化的文件路径
+ */
+func (gs *GameScene) OnFileChanged(path string) {
+	if gs.reloadRequested || gs.playerObject == nil {
+		return
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".tsj":
+		// 图块集可能被任意地图引用，直接重建
+	case ".tmj", ".ldtk":
+		mapPath, _, _ := strings.Cut(gs.sessionData.GetMapPath(), "#")
+		if filepath.Clean(mapPath) != filepath.Clean(path) {
+			return
+		}
+	default:
+		return
+	}
+
+	slog.Info("level file changed, rebuild game scene", slog.String("path", path))
+	gs.reloadRequested = true
+	nextScene := NewGameScene(gs.GetContext(), gs.SceneManager, gs.sessionData)
+	nextScene.reloadState = &reloadState{
+		playerPosition: gs.playerObject.GetComponent(def.ComponentTypeTransform).(*component.TransformComponent).GetPosition(),
+		cameraPosition: gs.GetContext().Camera.GetPosition(),
+	}
+	gs.SceneManager.RequestReplaceScene(nextScene)
+}
+
+// 更新关卡剩余时间，时间耗尽则判断为失败
+func (gs *GameScene) updateTimeLimit(dt float64) {
+	if gs.timeLabel == nil || gs.timeRemaining <= 0.0 {
+		return
+	}
+
+	prevSeconds := int(math.Ceil(gs.timeRemaining))
+	gs.timeRemaining = max(0.0, gs.timeRemaining-dt)
+	curSeconds := int(math.Ceil(gs.timeRemaining))
+	// 秒数变化时才更新标签文本
+	if curSeconds != prevSeconds {
+		gs.timeLabel.SetText("Time: " + strconv.Itoa(curSeconds))
+	}
+	if gs.timeRemaining <= 0.0 {
+		slog.Info("level time limit reached")
+		gs.showEndScene(false)
+	}
+}
+
 // 处理瓦片触发事件，从PhysicsEngine获取信息
 func (gs *GameScene) handleTileTriggers() {
 	// 从物理引擎获取触发事件
@@ -298,6 +486,8 @@ func (gs *GameScene) handlePlayerDamage(damage int) {
 		// 没有受伤，直接返回
 		return
 	}
+	// 受伤时画面震动
+	gs.GetContext().Camera.AddTrauma(cameraDamageTrauma)
 	if playerCom.IsDead() {
 		slog.Info("player dead", slog.String("name", gs.playerObject.GetName()))
 		// TODO: 可能的死亡逻辑处理
@@ -354,14 +544,26 @@ func (gs *GameScene) showEndScene(isWin bool) {
 	gs.SceneManager.RequestPushScene(endScene)
 }
 
-// 进入下一个关卡
+// 进入下一个关卡，圆形转场收缩到玩家处，再从下一关卡的玩家处张开
 func (gs *GameScene) toNextLevel(trigger *object.GameObject) {
+	// 转场期间玩家仍停留在触发器中，避免重复请求
+	if gs.SceneManager.IsTransitioning() {
+		return
+	}
+	// 触发器名称即下一关卡名称，触发器未命名时使用关卡属性中的next_level
 	sceneName := trigger.GetName()
+	if sceneName == "" || sceneName == "Unnamed" {
+		sceneName = gs.levelLoader.GetLevelProperties().NextLevel
+	}
+	if sceneName == "" {
+		slog.Error("next level not specified", slog.String("levelPath", gs.sessionData.GetMapPath()))
+		return
+	}
 	mapPath := gs.levelNameToPath(sceneName)
 	// 设置下一个关卡信息
 	gs.sessionData.SetNextLevel(mapPath)
 	nextScene := NewGameScene(gs.GetContext(), gs.SceneManager, gs.sessionData)
-	gs.SceneManager.RequestReplaceScene(nextScene)
+	gs.SceneManager.RequestReplaceSceneWithTransition(nextScene, escene.NewIrisTransition(levelIrisDuration, transitionColor))
 }
 
 // 玩家与敌人碰撞处理
@@ -376,14 +578,8 @@ func (gs *GameScene) playerVSEnemyCollision(player, enemy *object.GameObject) {
 	// 踩踏判断成功，敌人受伤
 	if playerCenter.Y() < enemyCenter.Y() && overlap.X() > overlap.Y() {
 		slog.Info("player stomped on enemy", slog.String("playerName", player.GetName()), slog.String("enemyName", enemy.GetName()))
-		enemyAIComp := enemy.GetComponent(def.ComponentTypeAI).(*component.AIComponent)
-		if enemyAIComp == nil {
-			slog.Error("enemy AI component not found", slog.String("enemyName", enemy.GetName()))
-			return
-		}
 		// 造成1点伤害
-		enemyAIComp.TakeDamage(1)
-		if !enemyAIComp.IsAlive() {
+		if gs.damageEnemy(enemy, 1) {
 			slog.Info("enemy is dead", slog.String("enemyName", enemy.GetName()))
 			enemy.SetNeedRemove(true)
 			// 敌人死亡后，创建死亡特效
@@ -393,6 +589,8 @@ func (gs *GameScene) playerVSEnemyCollision(player, enemy *object.GameObject) {
 		}
 		// 玩家跳起效果
 		player.GetComponent(def.ComponentTypePhysics).(*component.PhysicsComponent).Velocity[1] = -300.0
+		// 踩踏时画面向下一顿
+		gs.GetContext().Camera.Kick(cameraStompKick, cameraStompTrauma)
 		// 播放音效，此音效完全可以放在玩家的音频组件中，这里示例另一种用法：直接用AudioPlayer播放，传入文件路径
 		gs.GetContext().GetAudioPlayer().PlaySound("assets/audio/punch2a.mp3")
 		// 加分
@@ -404,6 +602,28 @@ func (gs *GameScene) playerVSEnemyCollision(player, enemy *object.GameObject) {
 	}
 }
 
+/**
+ * @brief 对敌人造成伤害
+ *
+ * 有AI组件时通过AI组件处理伤害，没有AI组件时(例如"ai"属性无效)直接扣除生命组件的生命值，
+ * 两者都没有的敌人不会受伤，玩家踩踏时只会弹起。
+ * @param enemy 敌人
+ * @param damage 伤害值
+ * @return bool 敌人是否死亡
+ */
+func (gs *GameScene) damageEnemy(enemy *object.GameObject, damage int) bool {
+	if enemyAIComp, ok := enemy.GetComponent(def.ComponentTypeAI).(*component.AIComponent); ok {
+		enemyAIComp.TakeDamage(damage)
+		return !enemyAIComp.IsAlive()
+	}
+	if healthComp, ok := enemy.GetComponent(def.ComponentTypeHealth).(*component.HealthComponent); ok {
+		healthComp.TakeDamage(damage)
+		return !healthComp.IsAlive()
+	}
+	slog.Warn("enemy has neither ai nor health component, can not be damaged", slog.String("enemyName", enemy.GetName()))
+	return false
+}
+
 // 玩家与道具碰撞处理
 func (gs *GameScene) playerVSItemCollision(player, item *object.GameObject) {
 	_ = player
@@ -420,6 +640,9 @@ func (gs *GameScene) playerVSItemCollision(player, item *object.GameObject) {
 	itemAABB := item.GetComponent(def.ComponentTypeCollider).(*component.ColliderComponent).GetWorldAABB()
 	// 创建特效
 	gs.createEffect(itemAABB.Position.Add(itemAABB.Size.Mul(0.5)), item.GetTag())
+	if item.GetName() == "gem" {
+		gs.createParticles(itemAABB.Position.Add(itemAABB.Size.Mul(0.5)), sparkleParticles)
+	}
 	// 播放音效，此音效完全可以放在玩家的音频组件中，这里示例另一种用法：直接用AudioPlayer播放，传入文件路径
 	gs.GetContext().GetAudioPlayer().PlaySound("assets/audio/poka01.mp3")
 }
@@ -464,6 +687,47 @@ func (gs *GameScene) createEffect(centerPos mgl32.Vec2, tag string) {
 	gs.SafeAddGameObject(effectObj)
 }
 
+// 玩家从空中落地时在脚下产生扬尘
+func (gs *GameScene) updateLandingDust() {
+	if gs.playerObject == nil {
+		return
+	}
+	physicsComp := gs.playerObject.GetComponent(def.ComponentTypePhysics).(*component.PhysicsComponent)
+	grounded := physicsComp.HasCollidedBelow()
+	// 落地的这一帧速度已被碰撞清零，使用上一帧记录的下落速度
+	if grounded && !gs.wasGrounded && gs.lastFallSpeed >= dustMinFallSpeed {
+		aabb := gs.playerObject.GetComponent(def.ComponentTypeCollider).(*component.ColliderComponent).GetWorldAABB()
+		gs.createParticles(mgl32.Vec2{aabb.Position.X() + aabb.Size.X()*0.5, aabb.Position.Y() + aabb.Size.Y()}, dustParticles)
+	}
+	gs.wasGrounded = grounded
+	gs.lastFallSpeed = physicsComp.Velocity.Y()
+}
+
+/**
+ * @brief 创建一个一次性的粒子特效对象，粒子全部消失后自动移除
+ * @param position 发射位置
+ * @param configPath 粒子发射器配置路径，加载后缓存
+ */
+func (gs *GameScene) createParticles(position mgl32.Vec2, configPath string) {
+	config, ok := gs.particleConfigs[configPath]
+	if !ok {
+		config = component.LoadParticleEmitterConfig(configPath)
+		if gs.particleConfigs == nil {
+			gs.particleConfigs = make(map[string]*component.ParticleEmitterConfig)
+		}
+		// 加载失败也缓存，避免每次重复读取
+		gs.particleConfigs[configPath] = config
+	}
+	if config == nil {
+		return
+	}
+
+	particlesObj := object.NewGameObject("particles", "effect")
+	particlesObj.AddComponent(component.NewTransformComponent(position, mgl32.Vec2{1.0, 1.0}, 0.0))
+	particlesObj.AddComponent(component.NewParticleEmitterComponent(config, gs.GetContext().ResourceManager))
+	gs.SafeAddGameObject(particlesObj)
+}
+
 // 测试保存和加载
 func (gs *GameScene) testSaveAndLoad() {
 	inputManager := gs.GetContext().GetInputManager()
@@ -497,6 +761,17 @@ func (gs *GameScene) createScoreUI() {
 	gs.UIManager.AddElement(gs.scoreLabel)
 }
 
+// 创建剩余时间UI
+func (gs *GameScene) createTimeUI() {
+	timeText := "Time: " + strconv.Itoa(int(math.Ceil(gs.timeRemaining)))
+	gs.timeLabel = ui.NewUILabel(gs.GetContext().TextRenderer, timeText, "assets/fonts/VonwaonBitmap-16px.ttf", 16,
+		emath.FColor{R: 1.0, G: 1.0, B: 1.0, A: 1.0}, mgl32.Vec2{0.0, 0.0})
+	// 放在屏幕上方居中
+	screenSize := gs.UIManager.GetRootElement().GetSize()
+	gs.timeLabel.SetPosition(mgl32.Vec2{(screenSize.X() - gs.timeLabel.GetSize().X()) * 0.5, 10.0})
+	gs.UIManager.AddElement(gs.timeLabel)
+}
+
 // 创建生命值UI (或最大生命值改变时重设)
 func (gs *GameScene) createHealthPanel() {
 	maxHealth := gs.sessionData.GetMaxHealth()
@@ -23,6 +23,16 @@ type MenuScene struct {
 	sessionData *data.SessionData
 }
 
+const (
+	// 菜单边框纹理及其边框宽度(像素)
+	menuFrameTexture = "assets/textures/UI/frame.png"
+	menuFrameInset   = 5.0
+	// 边框内侧与内容的间距
+	menuPanelPadding = 20.0
+	// 标题标签与按钮之间的间距
+	menuTitleGap = 38.0
+)
+
 // 确保MenuScene实现IScene接口
 var _ escene.IScene = (*MenuScene)(nil)
 
@@ -72,7 +82,20 @@ func (ms *MenuScene) CreateUI() {
 		return
 	}
 
-	// "PAUSE"标签
+	// 九宫格边框背景，根据内容自动调整大小，放在中间靠上的位置
+	framePanel := ui.NewUINineSlicePanel(menuFrameTexture, mgl32.Vec2{0.0, 0.0}, mgl32.Vec2{0.0, 0.0},
+		emath.UniformInsets(menuFrameInset), nil)
+	framePanel.SetFitContent(true, emath.UniformInsets(menuPanelPadding))
+	framePanel.SetAnchor(mgl32.Vec2{0.5, 0.2}, ui.AnchorTop, mgl32.Vec2{0.0, 0.0})
+	ms.UIManager.AddElement(framePanel)
+
+	// 标签与按钮从上到下排列，在边框中居中
+	buttonSize := mgl32.Vec2{96.0, 32.0}
+	content := ui.NewUIStackPanel(ui.StackVertical, 10.0, emath.Insets{})
+	content.SetAnchor(ui.AnchorCenter, ui.AnchorCenter, mgl32.Vec2{0.0, 0.0})
+	framePanel.AddChild(content)
+
+	// "PAUSE"标签，与按钮之间留出更大的间距
 	pauseLabel := ui.NewUILabel(ms.GetContext().GetTextRenderer(),
 		"PAUSE",
 		"assets/fonts/VonwaonBitmap-16px.ttf",
@@ -80,69 +103,55 @@ func (ms *MenuScene) CreateUI() {
 		emath.FColor{R: 1.0, G: 1.0, B: 1.0, A: 1.0},
 		mgl32.Vec2{0.0, 0.0},
 	)
-
-	// 放在中间靠上的位置
-	size := pauseLabel.GetSize()
-	labelY := screenSize.Y() * 0.2
-	pauseLabel.SetPosition(mgl32.Vec2{(screenSize.X() - size.X()) / 2.0, labelY})
-	ms.UIManager.AddElement(pauseLabel)
-
-	// 创建按钮(4个按钮，设定好大小、间距)
-	// 按钮稍微小一点
-	buttonWidth := float32(96.0)
-	buttonHeight := float32(32.0)
-	buttonSpacing := float32(10.0)
-	// 从标签下方开始，增加间距
-	startY := labelY + 80.0
-	// 水平居中
-	buttonX := (screenSize.X() - buttonWidth) / 2.0
+	pauseLabel.SetMargin(emath.Insets{Bottom: menuTitleGap})
+	content.AddChild(pauseLabel)
 
 	// Resume Button
 	resumeButton := ui.NewUIButton(ms.GetContext(),
 		"assets/textures/UI/buttons/Resume1.png",
 		"assets/textures/UI/buttons/Resume2.png",
 		"assets/textures/UI/buttons/Resume3.png",
-		mgl32.Vec2{buttonX, startY},
-		mgl32.Vec2{buttonWidth, buttonHeight},
+		mgl32.Vec2{0.0, 0.0},
+		buttonSize,
 		func() { ms.onResumeClicked() },
 	)
-	ms.UIManager.AddElement(resumeButton)
+	content.AddChild(resumeButton)
 
 	// Save Button
-	startY += buttonHeight + buttonSpacing
 	saveButton := ui.NewUIButton(ms.GetContext(),
 		"assets/textures/UI/buttons/Save1.png",
 		"assets/textures/UI/buttons/Save2.png",
 		"assets/textures/UI/buttons/Save3.png",
-		mgl32.Vec2{buttonX, startY},
-		mgl32.Vec2{buttonWidth, buttonHeight},
+		mgl32.Vec2{0.0, 0.0},
+		buttonSize,
 		func() { ms.onSaveClicked() },
 	)
-	ms.UIManager.AddElement(saveButton)
+	content.AddChild(saveButton)
 
 	// Back Button
-	startY += buttonHeight + buttonSpacing
 	backButton := ui.NewUIButton(ms.GetContext(),
 		"assets/textures/UI/buttons/Back1.png",
 		"assets/textures/UI/buttons/Back2.png",
 		"assets/textures/UI/buttons/Back3.png",
-		mgl32.Vec2{buttonX, startY},
-		mgl32.Vec2{buttonWidth, buttonHeight},
+		mgl32.Vec2{0.0, 0.0},
+		buttonSize,
 		func() { ms.onBackClicked() },
 	)
-	ms.UIManager.AddElement(backButton)
+	content.AddChild(backButton)
 
 	// Quit Button
-	startY += buttonHeight + buttonSpacing
 	quitButton := ui.NewUIButton(ms.GetContext(),
 		"assets/textures/UI/buttons/Quit1.png",
 		"assets/textures/UI/buttons/Quit2.png",
 		"assets/textures/UI/buttons/Quit3.png",
-		mgl32.Vec2{buttonX, startY},
-		mgl32.Vec2{buttonWidth, buttonHeight},
+		mgl32.Vec2{0.0, 0.0},
+		buttonSize,
 		func() { ms.onQuitClicked() },
 	)
-	ms.UIManager.AddElement(quitButton)
+	content.AddChild(quitButton)
+
+	// 取消键(例如手柄的East键)同样恢复游戏
+	ms.UIManager.SetCancelCallback(func() { ms.onResumeClicked() })
 }
 
 // 按钮回调函数实现
@@ -165,9 +174,9 @@ func (ms *MenuScene) onSaveClicked() {
 
 // 返回按钮回调
 func (ms *MenuScene) onBackClicked() {
-	// 直接替换为TitleScene
-	ms.SceneManager.RequestReplaceScene(
-		NewTitleScene(ms.GetContext(), ms.SceneManager, ms.sessionData))
+	// 淡出后替换为TitleScene
+	ms.SceneManager.RequestReplaceSceneWithTransition(
+		NewTitleScene(ms.GetContext(), ms.SceneManager, ms.sessionData), newSceneFade())
 }
 
 // 退出按钮回调
@@ -82,79 +82,63 @@ func (ts *TitleScene) createUI() {
 	// 放大为2倍
 	titleImage.SetSize(size.Mul(2.0))
 
-	// 水平居中
-	titleImage.SetPosition(screenSize.Sub(titleImage.GetSize()).Mul(0.5).Sub(mgl32.Vec2{0.0, 50.0}))
+	// 居中并稍微靠上
+	titleImage.SetAnchor(ui.AnchorCenter, ui.AnchorCenter, mgl32.Vec2{0.0, -50.0})
 	ts.UIManager.AddElement(titleImage)
 
-	// 创建按钮面板并居中，4个按钮，设定好大小、间距
-	buttonWidth := float32(96.0)
-	buttonHeight := float32(32.0)
+	// 创建水平排列的按钮面板，4个按钮，设定好大小、间距
+	buttonSize := mgl32.Vec2{96.0, 32.0}
 	buttonSpacing := float32(20.0)
-	numButtons := 4
+	buttonPanel := ui.NewUIStackPanel(ui.StackHorizontal, buttonSpacing, emath.Insets{})
+	// 水平居中，垂直位置中间靠下
+	buttonPanel.SetAnchor(mgl32.Vec2{0.5, 0.65}, ui.AnchorTop, mgl32.Vec2{0.0, 0.0})
 
-	// 计算面板总宽高
-	panelWidth := float32(numButtons)*buttonWidth + (float32(numButtons-1))*buttonSpacing
-	panelHeight := buttonHeight
-
-	// 计算面板位置使其居中
-	panelX := (screenSize.X() - panelWidth) / 2.0
-	// 垂直位置中间靠下
-	panelY := screenSize.Y() * 0.65
-
-	// 创建按钮面板
-	buttonPanel := ui.NewUIPanel(mgl32.Vec2{panelX, panelY}, mgl32.Vec2{panelWidth, panelHeight}, nil)
-
-	// 创建按钮并添加到 UIPanel (位置是相对于 UIPanel 的 0,0)
-	curButtonPos := mgl32.Vec2{0.0, 0.0}
-	buttonSize := mgl32.Vec2{buttonWidth, buttonHeight}
+	// 创建按钮并添加到按钮面板，位置由面板排列
 	// Start Button
 	startButton := ui.NewUIButton(ts.GetContext(),
 		"assets/textures/UI/buttons/Start1.png",
 		"assets/textures/UI/buttons/Start2.png",
 		"assets/textures/UI/buttons/Start3.png",
-		curButtonPos,
+		mgl32.Vec2{},
 		buttonSize,
 		func() { ts.onStartGameClick() },
 	)
 	buttonPanel.AddChild(startButton)
 
 	// Load Button
-	curButtonPos[0] += buttonWidth + buttonSpacing
 	loadButton := ui.NewUIButton(ts.GetContext(),
 		"assets/textures/UI/buttons/Load1.png",
 		"assets/textures/UI/buttons/Load2.png",
 		"assets/textures/UI/buttons/Load3.png",
-		curButtonPos,
+		mgl32.Vec2{},
 		buttonSize,
 		func() { ts.onLoadGameClick() },
 	)
 	buttonPanel.AddChild(loadButton)
 
 	// Helps Button
-	curButtonPos[0] += buttonWidth + buttonSpacing
 	helpsButton := ui.NewUIButton(ts.GetContext(),
 		"assets/textures/UI/buttons/Helps1.png",
 		"assets/textures/UI/buttons/Helps2.png",
 		"assets/textures/UI/buttons/Helps3.png",
-		curButtonPos,
+		mgl32.Vec2{},
 		buttonSize,
 		func() { ts.onHelpsClick() },
 	)
 	buttonPanel.AddChild(helpsButton)
 
 	// Quit Button
-	curButtonPos[0] += buttonWidth + buttonSpacing
 	quitButton := ui.NewUIButton(ts.GetContext(),
 		"assets/textures/UI/buttons/Quit1.png",
 		"assets/textures/UI/buttons/Quit2.png",
 		"assets/textures/UI/buttons/Quit3.png",
-		curButtonPos,
+		mgl32.Vec2{},
 		buttonSize,
 		func() { ts.onQuitClick() },
 	)
 	buttonPanel.AddChild(quitButton)
 
-	// 将 UIPanel 添加到UI管理器
+	// 将按钮面板添加到UI管理器
 	ts.UIManager.AddElement(buttonPanel)
 
 	// 创建 Credits 标签
@@ -165,8 +149,8 @@ func (ts *TitleScene) createUI() {
 		emath.FColor{R: 0.8, G: 0.8, B: 0.8, A: 1.0},
 		mgl32.Vec2{0.0, 0.0},
 	)
-	creditsLabel.SetPosition(mgl32.Vec2{(screenSize.X() - creditsLabel.GetSize().X()) / 2.0,
-		screenSize.Y() - creditsLabel.GetSize().Y() - 10.0})
+	// 底部居中，与底边间隔10像素
+	creditsLabel.SetAnchor(ui.AnchorBottom, ui.AnchorBottom, mgl32.Vec2{0.0, -10.0})
 	ts.UIManager.AddElement(creditsLabel)
 }
 
@@ -183,7 +167,7 @@ func (ts *TitleScene) onStartGameClick() {
 	if ts.sessionData != nil {
 		ts.sessionData.Reset()
 	}
-	ts.SceneManager.RequestReplaceScene(NewGameScene(ts.GetContext(), ts.SceneManager, ts.sessionData))
+	ts.SceneManager.RequestReplaceSceneWithTransition(NewGameSceneWithLoading(ts.GetContext(), ts.SceneManager, ts.sessionData), newSceneFade())
 }
 
 // 加载游戏按钮点击回调
@@ -195,7 +179,7 @@ func (ts *TitleScene) onLoadGameClick() {
 
 	if ts.sessionData.LoadFromFile("assets/save.json") {
 		slog.Debug("save file load success, start game...")
-		ts.SceneManager.RequestReplaceScene(NewGameScene(ts.GetContext(), ts.SceneManager, ts.sessionData))
+		ts.SceneManager.RequestReplaceSceneWithTransition(NewGameSceneWithLoading(ts.GetContext(), ts.SceneManager, ts.sessionData), newSceneFade())
 	} else {
 		slog.Warn("load save file failed")
 	}